@@ -0,0 +1,122 @@
+package gcurl
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MediaRange is an entry of an Accept header, such as "text/*;q=0.5".
+type MediaRange struct {
+	Type    string
+	Subtype string
+	// Params holds the media type parameters, without q.
+	Params map[string]string
+	Q      float64
+}
+
+// String formats the range as in an Accept header.
+func (m MediaRange) String() string {
+	s := mime.FormatMediaType(m.Type+"/"+m.Subtype, m.Params)
+	if s == "" {
+		s = m.Type + "/" + m.Subtype
+	}
+	if m.Q != 1 {
+		s += "; q=" + strconv.FormatFloat(m.Q, 'f', -1, 64)
+	}
+	return s
+}
+
+// Matches reports whether mediaType, which may carry parameters, falls in
+// the range.
+func (m MediaRange) Matches(mediaType string) bool {
+	mt, params, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+	typ, sub, _ := strings.Cut(mt, "/")
+	if m.Type != "*" && m.Type != typ || m.Subtype != "*" && m.Subtype != sub {
+		return false
+	}
+	for k, v := range m.Params {
+		if !strings.EqualFold(params[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// specificity ranks ranges as RFC 9110 does: exact types with parameters
+// first, */* last.
+func (m MediaRange) specificity() int {
+	switch {
+	case m.Type == "*":
+		return 0
+	case m.Subtype == "*":
+		return 1
+	}
+	return 2 + len(m.Params)
+}
+
+// ParseAccept parses an Accept header into media ranges sorted by
+// decreasing q-value, then specificity. Invalid entries are skipped.
+func ParseAccept(header string) []MediaRange {
+	var res []MediaRange
+	for _, entry := range strings.Split(header, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		mt, params, err := mime.ParseMediaType(entry)
+		if err != nil {
+			continue
+		}
+		typ, sub, ok := strings.Cut(mt, "/")
+		if !ok || typ == "*" && sub != "*" {
+			continue
+		}
+
+		m := MediaRange{Type: typ, Subtype: sub, Q: 1}
+		if q, ok := params["q"]; ok {
+			delete(params, "q")
+			v, err := strconv.ParseFloat(q, 64)
+			if err != nil || v < 0 || v > 1 {
+				continue
+			}
+			m.Q = v
+		}
+		if len(params) > 0 {
+			m.Params = params
+		}
+		res = append(res, m)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Q != res[j].Q {
+			return res[i].Q > res[j].Q
+		}
+		return res[i].specificity() > res[j].specificity()
+	})
+	return res
+}
+
+// AcceptedMediaRanges returns the parsed Accept header of the request.
+func (r *Request) AcceptedMediaRanges() []MediaRange {
+	return ParseAccept(strings.Join(r.HeaderValues("accept"), ","))
+}
+
+// Accepts reports whether the request accepts responses of mediaType.
+// The most specific matching range decides, a q-value of 0 refusing the
+// type. Requests without Accept header accept everything.
+func (r *Request) Accepts(mediaType string) bool {
+	if !r.Header.Has("accept") {
+		return true
+	}
+	best := -1
+	var q float64
+	for _, m := range r.AcceptedMediaRanges() {
+		if s := m.specificity(); s > best && m.Matches(mediaType) {
+			best, q = s, m.Q
+		}
+	}
+	return best >= 0 && q > 0
+}
@@ -0,0 +1,40 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccept(t *testing.T) {
+	ranges := ParseAccept(`text/*;q=0.3, text/html;q=0.7, text/html;level=1, text/html;level=2;q=0.4, */*;q=0.5, bad, */html`)
+	require.Equal(t, []MediaRange{
+		{Type: "text", Subtype: "html", Params: map[string]string{"level": "1"}, Q: 1},
+		{Type: "text", Subtype: "html", Q: 0.7},
+		{Type: "*", Subtype: "*", Q: 0.5},
+		{Type: "text", Subtype: "html", Params: map[string]string{"level": "2"}, Q: 0.4},
+		{Type: "text", Subtype: "*", Q: 0.3},
+	}, ranges)
+
+	require.Equal(t, "text/html; level=1", ranges[0].String())
+	require.Equal(t, "*/*; q=0.5", ranges[2].String())
+	require.Empty(t, ParseAccept(""))
+	require.Empty(t, ParseAccept("text/html;q=2"))
+}
+
+func TestRequestAccepts(t *testing.T) {
+	req, err := Parse(`curl -H 'Accept: application/json, application/*;q=0.2, image/png;q=0' -H 'Accept: text/csv' https://api.site.com`)
+	require.NoError(t, err)
+
+	require.Len(t, req.AcceptedMediaRanges(), 4)
+	require.True(t, req.Accepts("application/json; charset=utf-8"))
+	require.True(t, req.Accepts("application/xml"))
+	require.True(t, req.Accepts("text/csv"))
+	require.False(t, req.Accepts("image/png"))
+	require.False(t, req.Accepts("text/html"))
+	require.False(t, req.Accepts("not a type"))
+
+	req, err = Parse(`curl https://api.site.com`)
+	require.NoError(t, err)
+	require.True(t, req.Accepts("image/png"))
+}
@@ -0,0 +1,126 @@
+package gcurl
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// AuthType is the authentication scheme of Request.Auth.
+type AuthType string
+
+const (
+	AuthBasic     AuthType = "basic"
+	AuthBearer    AuthType = "bearer"
+	AuthDigest    AuthType = "digest"
+	AuthNTLM      AuthType = "ntlm"
+	AuthNegotiate AuthType = "negotiate"
+	// AuthAny is --anyauth: the scheme is negotiated with the server.
+	AuthAny      AuthType = "any"
+	AuthAWSSigV4 AuthType = "aws-sigv4"
+)
+
+// Auth holds the credentials of -u, --oauth2-bearer and --aws-sigv4, and
+// the scheme chosen with --basic, --digest, --ntlm, --negotiate or
+// --anyauth. Only basic and bearer credentials are sent by ToHTTPRequest,
+// and SigV4 ones by WithSigV4Signing; the other schemes are recorded for
+// callers to implement.
+type Auth struct {
+	Type     AuthType `json:"type"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	// Token is the --oauth2-bearer token.
+	Token string `json:"token,omitempty"`
+	// AWSSigV4 is the --aws-sigv4 provider string, such as
+	// "aws:amz:us-east-1:s3". The key pair is given with -u.
+	AWSSigV4 string `json:"aws_sigv4,omitempty"`
+}
+
+// WithStructuredAuth keeps the credentials of -u and --oauth2-bearer in
+// Request.Auth only, instead of also baking them into the Authorization
+// header. ToHTTPRequest still sends basic and bearer credentials.
+func WithStructuredAuth() ParseOption {
+	return func(p *Parser) { p.structuredAuth = true }
+}
+
+// header returns the Authorization header sending the credentials, for
+// the basic and bearer schemes.
+func (a *Auth) header() (string, bool) {
+	switch a.Type {
+	case AuthBasic:
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(a.Username+":"+a.Password)), true
+	case AuthBearer:
+		return "Bearer " + a.Token, true
+	}
+	return "", false
+}
+
+// derivedHeader reports whether the Authorization header of r is the one
+// sending r.Auth.
+func (r *Request) derivedHeader() bool {
+	if r.Auth == nil {
+		return false
+	}
+	h, ok := r.Auth.header()
+	v, set := r.Header[KeyAuthorization]
+	return ok && set && v == h
+}
+
+// userArg parses a -u argument into the request's Auth.
+func userArg(req *Request, arg string) {
+	if req.Auth == nil {
+		req.Auth = &Auth{}
+	}
+	req.Auth.Username, req.Auth.Password, _ = strings.Cut(arg, ":")
+}
+
+// finishAuth settles the scheme of the request's Auth once all options are
+// read, as curl's are order-independent. An Authorization header given
+// with -H takes precedence over the derived one, like in curl.
+func (p *Parser) finishAuth(req *Request, authType AuthType) {
+	if req.Auth == nil {
+		return
+	}
+	switch {
+	case authType != "":
+		req.Auth.Type = authType
+	case req.Auth.Token != "":
+		req.Auth.Type = AuthBearer
+	default:
+		req.Auth.Type = AuthBasic
+	}
+	if p.structuredAuth {
+		return
+	}
+	if h, ok := req.Auth.header(); ok && !req.Header.Has(KeyAuthorization) {
+		req.Header[KeyAuthorization] = h
+	}
+}
+
+// curlArgs returns the options giving the credentials in a curl command.
+func (a *Auth) curlArgs() []string {
+	var args []string
+	// An Auth without credentials comes from -u alone, as in "-u :".
+	if a.Username != "" || a.Password != "" || a.Token == "" && a.AWSSigV4 == "" {
+		args = append(args, "-u", shellQuote(a.Username+":"+a.Password))
+	}
+	if a.Token != "" {
+		args = append(args, "--oauth2-bearer", shellQuote(a.Token))
+	}
+	switch a.Type {
+	case AuthBasic:
+		if a.Token != "" {
+			args = append(args, "--basic")
+		}
+	case AuthDigest:
+		args = append(args, "--digest")
+	case AuthNTLM:
+		args = append(args, "--ntlm")
+	case AuthNegotiate:
+		args = append(args, "--negotiate")
+	case AuthAny:
+		args = append(args, "--anyauth")
+	case AuthAWSSigV4:
+		args = append(args, "--aws-sigv4", shellQuote(a.AWSSigV4))
+	}
+	return args
+}
@@ -0,0 +1,146 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuth(t *testing.T) {
+	var tests = []struct {
+		name   string
+		given  string
+		auth   *Auth
+		header string
+		curl   string
+	}{
+		{
+			"basic",
+			`curl -u sid:pa55 https://api.site.com`,
+			&Auth{Type: AuthBasic, Username: "sid", Password: "pa55"},
+			"Basic c2lkOnBhNTU=",
+			`curl -u sid:pa55 https://api.site.com`,
+		},
+		{
+			"explicit header wins",
+			`curl -H 'Authorization: Token abc' -u sid:pa55 https://api.site.com`,
+			&Auth{Type: AuthBasic, Username: "sid", Password: "pa55"},
+			"Token abc",
+			`curl -H 'authorization: Token abc' -u sid:pa55 https://api.site.com`,
+		},
+		{
+			"digest",
+			`curl --digest --user 'sid:pa:55' https://api.site.com`,
+			&Auth{Type: AuthDigest, Username: "sid", Password: "pa:55"},
+			"",
+			`curl -u sid:pa:55 --digest https://api.site.com`,
+		},
+		{
+			"ntlm",
+			`curl -u 'CORP\sid:pa55' --ntlm https://api.site.com`,
+			&Auth{Type: AuthNTLM, Username: `CORP\sid`, Password: "pa55"},
+			"",
+			`curl -u 'CORP\sid:pa55' --ntlm https://api.site.com`,
+		},
+		{
+			"negotiate",
+			`curl --negotiate -u : https://api.site.com`,
+			&Auth{Type: AuthNegotiate},
+			"",
+			`curl -u : --negotiate https://api.site.com`,
+		},
+		{
+			"anyauth",
+			`curl --anyauth -u sid:pa55 https://api.site.com`,
+			&Auth{Type: AuthAny, Username: "sid", Password: "pa55"},
+			"",
+			`curl -u sid:pa55 --anyauth https://api.site.com`,
+		},
+		{
+			"aws sigv4",
+			`curl --aws-sigv4 'aws:amz:us-east-1:s3' -u AKIA:s3cr3t https://bucket.s3.amazonaws.com`,
+			&Auth{Type: AuthAWSSigV4, Username: "AKIA", Password: "s3cr3t", AWSSigV4: "aws:amz:us-east-1:s3"},
+			"",
+			`curl -u AKIA:s3cr3t --aws-sigv4 aws:amz:us-east-1:s3 https://bucket.s3.amazonaws.com`,
+		},
+		{
+			"bearer",
+			`curl --oauth2-bearer t0k3n https://api.site.com`,
+			&Auth{Type: AuthBearer, Token: "t0k3n"},
+			"Bearer t0k3n",
+			`curl --oauth2-bearer t0k3n https://api.site.com`,
+		},
+		{
+			"no credentials",
+			`curl --digest https://api.site.com`,
+			nil,
+			"",
+			`curl https://api.site.com`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := Parse(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.auth, req.Auth)
+			require.Equal(t, tt.header, req.Header[KeyAuthorization])
+			require.Equal(t, tt.curl, req.curl())
+
+			back, err := Parse(req.curl())
+			require.NoError(t, err)
+			require.Equal(t, req, back)
+		})
+	}
+}
+
+func TestStructuredAuth(t *testing.T) {
+	p := NewParser(WithStructuredAuth())
+	req, err := p.Parse(`curl -u sid:pa55 https://api.site.com`)
+	require.NoError(t, err)
+	require.Empty(t, req.Header)
+	require.Equal(t, &Auth{Type: AuthBasic, Username: "sid", Password: "pa55"}, req.Auth)
+
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	user, pass, ok := hreq.BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "sid", user)
+	require.Equal(t, "pa55", pass)
+
+	req, err = p.Parse(`curl --digest -u sid:pa55 https://api.site.com`)
+	require.NoError(t, err)
+	hreq, err = req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, hreq.Header.Get("Authorization"), "digest is left to the caller")
+
+	req, err = p.Parse(`curl --oauth2-bearer t0k3n https://api.site.com`)
+	require.NoError(t, err)
+	hreq, err = req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.Header{"Authorization": {"Bearer t0k3n"}}, hreq.Header)
+}
+
+func TestAuthSecrets(t *testing.T) {
+	req, err := Parse(`curl -u sid:pa55 https://api.site.com`)
+	require.NoError(t, err)
+	require.Equal(t, `curl -u sid:REDACTED https://api.site.com`, req.StringRedacted())
+
+	tokenized, values := Tokenize(req)
+	require.Equal(t, map[string]string{"{{TOKEN_1}}": "pa55"}, values)
+	require.Equal(t, `curl -u 'sid:{{TOKEN_1}}' https://api.site.com`, tokenized.curl())
+	require.Equal(t, req, Hydrate(tokenized, values))
+}
+
+func TestAuthBinary(t *testing.T) {
+	req, err := Parse(`curl --aws-sigv4 'aws:amz:us-east-1:s3' -u AKIA:s3cr3t https://bucket.s3.amazonaws.com`)
+	require.NoError(t, err)
+	data, err := req.MarshalBinary()
+	require.NoError(t, err)
+	back := &Request{}
+	require.NoError(t, back.UnmarshalBinary(data))
+	require.Equal(t, req, back)
+}
@@ -0,0 +1,283 @@
+package gcurl
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBatchConcurrency is used when BatchRunner.Concurrency is zero.
+const DefaultBatchConcurrency = 8
+
+// BatchRunner executes many requests concurrently, sharing connections
+// between them.
+type BatchRunner struct {
+	// Concurrency caps the number of requests in flight.
+	Concurrency int
+	// PerHostConcurrency caps the requests in flight to a single host
+	// (host:port). Zero means only the global limit applies.
+	PerHostConcurrency int
+	// GracePeriod is how long requests in flight may complete once the
+	// context is canceled, after which they are aborted. Zero aborts them
+	// at once.
+	GracePeriod time.Duration
+	// Override, if set, returns the overrides of a request given its
+	// index, or nil to execute it as is.
+	Override func(index int, req *Request) *BatchOverride
+	// Options apply to every request of the batch.
+	Options []ExecOption
+}
+
+// BatchStatus tells how a request of a batch ended.
+type BatchStatus string
+
+const (
+	BatchCompleted BatchStatus = "completed"
+	BatchFailed    BatchStatus = "failed"
+	// BatchCancelled is the status of requests that were not started, or
+	// were aborted, because the batch context was canceled.
+	BatchCancelled BatchStatus = "cancelled"
+)
+
+// ResultSink receives the results of BatchRunner.RunSink.
+type ResultSink interface {
+	Put(res BatchResult) error
+}
+
+// ResultSinkFunc adapts a function to a ResultSink.
+type ResultSinkFunc func(res BatchResult) error
+
+func (f ResultSinkFunc) Put(res BatchResult) error {
+	return f(res)
+}
+
+// ChanSink sends results to a channel, blocking until they are received.
+type ChanSink chan<- BatchResult
+
+func (c ChanSink) Put(res BatchResult) error {
+	c <- res
+	return nil
+}
+
+type BatchResult struct {
+	Index    int
+	Request  *Request
+	Response *Response
+	Err      error
+	Status   BatchStatus
+	// Attempts is the number of times the request was sent.
+	Attempts int
+}
+
+// Run executes reqs and returns their results in input order. Once ctx is
+// canceled no more requests are started, and those in flight are given
+// GracePeriod to complete: the results of a canceled run are partial.
+func (b *BatchRunner) Run(ctx context.Context, reqs []*Request) []BatchResult {
+	run, done := b.start(ctx)
+	defer done()
+
+	results := make([]BatchResult, len(reqs))
+	wg := &sync.WaitGroup{}
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req *Request) {
+			defer wg.Done()
+			results[i] = run.exec(i, req)
+		}(i, req)
+	}
+	wg.Wait()
+	return results
+}
+
+// RunSink executes the requests received on reqs until it is closed or
+// ctx is canceled, putting each result into sink as it completes, so
+// that large replays don't hold their results in memory. Result indexes
+// count requests in the order received, and Put is never called
+// concurrently. A sink error cancels the run and is returned; otherwise
+// RunSink returns ctx.Err(). Cancellation is handled as in Run.
+func (b *BatchRunner) RunSink(ctx context.Context, reqs <-chan *Request, sink ResultSink) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	run, done := b.start(ctx)
+	defer done()
+
+	// Requests waiting for a busy host don't hold global slots, so more
+	// than Concurrency are admitted to keep the other hosts busy.
+	pending := make(chan struct{}, 2*cap(run.global))
+	mu := &sync.Mutex{}
+	var sinkErr error
+	wg := &sync.WaitGroup{}
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+		case pending <- struct{}{}:
+		}
+		var req *Request
+		ok := false
+		if ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case req, ok = <-reqs:
+			}
+		}
+		if !ok {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, req *Request) {
+			defer wg.Done()
+			defer func() { <-pending }()
+			res := run.exec(i, req)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if sinkErr != nil {
+				return
+			}
+			if sinkErr = sink.Put(res); sinkErr != nil {
+				cancel(sinkErr)
+			}
+		}(i, req)
+	}
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+// batchRun is the state shared by the requests of a run.
+type batchRun struct {
+	// ctx stops starting requests, runCtx aborts those in flight.
+	ctx, runCtx context.Context
+	cfg         *execConfig
+	global      chan struct{}
+	hosts       *hostLimiter
+	override    func(index int, req *Request) *BatchOverride
+}
+
+// start sets up a run of the runner's requests. done releases it.
+func (b *BatchRunner) start(ctx context.Context) (run *batchRun, done func()) {
+	cfg := newExecConfig(b.Options)
+
+	// Requests run on their own context, canceled when the grace period
+	// following the cancellation of ctx is over.
+	runCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		if b.GracePeriod <= 0 {
+			abort()
+			return
+		}
+		cfg.afterFunc(b.GracePeriod, abort)
+	})
+
+	concurrency := b.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	run = &batchRun{
+		ctx:      ctx,
+		runCtx:   runCtx,
+		cfg:      cfg,
+		global:   make(chan struct{}, concurrency),
+		hosts:    &hostLimiter{limit: b.PerHostConcurrency},
+		override: b.Override,
+	}
+	return run, func() {
+		stop()
+		abort()
+		cfg.closeIdleConnections()
+	}
+}
+
+// exec executes the i-th request of the run once slots are available.
+func (r *batchRun) exec(i int, req *Request) BatchResult {
+	res := BatchResult{Index: i, Request: req}
+
+	// Take the host slot first so that requests waiting on a busy
+	// host don't hold global slots other hosts could use.
+	release, err := r.hosts.acquire(r.ctx, requestHost(req))
+	if err != nil {
+		res.Err, res.Status = err, BatchCancelled
+		return res
+	}
+	defer release()
+
+	select {
+	case <-r.ctx.Done():
+		res.Err, res.Status = r.ctx.Err(), BatchCancelled
+		return res
+	case r.global <- struct{}{}:
+	}
+	defer func() { <-r.global }()
+	if err := r.ctx.Err(); err != nil {
+		res.Err, res.Status = err, BatchCancelled
+		return res
+	}
+
+	effective := req
+	var override *BatchOverride
+	if r.override != nil {
+		if override = r.override(i, req); override != nil {
+			effective = override.apply(req)
+		}
+	}
+	for {
+		res.Attempts++
+		res.Response, res.Err = effective.do(r.runCtx, r.cfg)
+		if override == nil || r.runCtx.Err() != nil || !override.retry(effective, res.Attempts, res.Response, res.Err) {
+			break
+		}
+		if r.cfg.wait(r.ctx, override.RetryDelay) != nil {
+			break
+		}
+	}
+	switch {
+	case res.Err == nil:
+		res.Status = BatchCompleted
+	case r.runCtx.Err() != nil:
+		res.Status = BatchCancelled
+	default:
+		res.Status = BatchFailed
+	}
+	return res
+}
+
+type hostLimiter struct {
+	limit int
+
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+func (l *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	if l.limit <= 0 {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	if l.sems == nil {
+		l.sems = map[string]chan struct{}{}
+	}
+	sem, ok := l.sems[host]
+	if !ok {
+		sem = make(chan struct{}, l.limit)
+		l.sems[host] = sem
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	}
+}
+
+func requestHost(req *Request) string {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
@@ -0,0 +1,189 @@
+package gcurl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyServer records the maximum number of requests it served at once.
+type concurrencyServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func newConcurrencyServer() *concurrencyServer {
+	s := &concurrencyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.current++
+		if s.current > s.max {
+			s.max = s.current
+		}
+		s.mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		s.mu.Lock()
+		s.current--
+		s.mu.Unlock()
+	}))
+	return s
+}
+
+func TestBatchRunnerPerHostConcurrency(t *testing.T) {
+	a, b := newConcurrencyServer(), newConcurrencyServer()
+	defer a.Close()
+	defer b.Close()
+
+	var reqs []*Request
+	for i := 0; i < 10; i++ {
+		reqs = append(reqs,
+			&Request{Method: "GET", URL: a.URL, Header: Header{}},
+			&Request{Method: "GET", URL: b.URL, Header: Header{}},
+		)
+	}
+
+	runner := &BatchRunner{Concurrency: 6, PerHostConcurrency: 2}
+	results := runner.Run(context.Background(), reqs)
+	require.Len(t, results, 20)
+	for i, res := range results {
+		require.Equal(t, i, res.Index)
+		require.Same(t, reqs[i], res.Request)
+		require.NoError(t, res.Err)
+		require.Equal(t, http.StatusOK, res.Response.StatusCode)
+	}
+	require.LessOrEqual(t, a.max, 2)
+	require.LessOrEqual(t, b.max, 2)
+}
+
+func TestBatchRunnerGlobalConcurrency(t *testing.T) {
+	srv := newConcurrencyServer()
+	defer srv.Close()
+
+	var reqs []*Request
+	for i := 0; i < 9; i++ {
+		reqs = append(reqs, &Request{Method: "GET", URL: srv.URL, Header: Header{}})
+	}
+
+	results := (&BatchRunner{Concurrency: 3}).Run(context.Background(), reqs)
+	for _, res := range results {
+		require.NoError(t, res.Err)
+	}
+	require.LessOrEqual(t, srv.max, 3)
+}
+
+func TestBatchRunnerCancel(t *testing.T) {
+	var tests = []struct {
+		name      string
+		runner    *BatchRunner
+		completed int
+	}{
+		{"grace period", &BatchRunner{Concurrency: 1, GracePeriod: time.Hour, Options: []ExecOption{WithClock(stubClock{})}}, 1},
+		{"grace period over", &BatchRunner{Concurrency: 1, GracePeriod: time.Hour, Options: []ExecOption{WithClock(stubClock{fire: true})}}, 0},
+		{"no grace period", &BatchRunner{Concurrency: 1}, 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// The first request blocks until the batch is canceled, and
+			// with a single slot no other one is started.
+			var hits int32
+			started, release := make(chan struct{}), make(chan struct{})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&hits, 1) == 1 {
+					close(started)
+				}
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}))
+			defer srv.Close()
+
+			reqs := make([]*Request, 3)
+			for i := range reqs {
+				reqs[i] = &Request{Method: http.MethodGet, URL: srv.URL, Header: Header{}}
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				<-started
+				cancel()
+				close(release)
+			}()
+			results := tt.runner.Run(ctx, reqs)
+
+			require.Equal(t, int32(1), atomic.LoadInt32(&hits))
+			var completed int
+			for i, res := range results {
+				require.Equal(t, i, res.Index)
+				if res.Status == BatchCompleted {
+					completed++
+					require.NoError(t, res.Err)
+					require.Equal(t, http.StatusOK, res.Response.StatusCode)
+					continue
+				}
+				require.Equal(t, BatchCancelled, res.Status)
+				require.ErrorIs(t, res.Err, context.Canceled)
+			}
+			require.Equal(t, tt.completed, completed)
+		})
+	}
+}
+
+func TestBatchRunnerRunSink(t *testing.T) {
+	srv := newConcurrencyServer()
+	defer srv.Close()
+
+	const n = 20
+	reqs := make(chan *Request, n)
+	for i := 0; i < n; i++ {
+		reqs <- &Request{Method: http.MethodGet, URL: srv.URL, Header: Header{}}
+	}
+	close(reqs)
+
+	seen := map[int]bool{}
+	err := (&BatchRunner{Concurrency: 4}).RunSink(context.Background(), reqs, ResultSinkFunc(func(res BatchResult) error {
+		require.False(t, seen[res.Index])
+		seen[res.Index] = true
+		require.Equal(t, BatchCompleted, res.Status)
+		return nil
+	}))
+	require.NoError(t, err)
+	require.Len(t, seen, n)
+	require.LessOrEqual(t, srv.max, 4)
+
+	// A sink error stops the run.
+	reqs = make(chan *Request, n)
+	for i := 0; i < n; i++ {
+		reqs <- &Request{Method: http.MethodGet, URL: srv.URL, Header: Header{}}
+	}
+	close(reqs)
+	var puts int
+	err = (&BatchRunner{Concurrency: 1}).RunSink(context.Background(), reqs, ResultSinkFunc(func(res BatchResult) error {
+		puts++
+		return errors.New("sink full")
+	}))
+	require.EqualError(t, err, "sink full")
+	require.Equal(t, 1, puts)
+	require.NotEmpty(t, reqs)
+
+	// Results can be consumed from a channel.
+	reqs = make(chan *Request, 1)
+	reqs <- &Request{Method: http.MethodGet, URL: srv.URL, Header: Header{}}
+	close(reqs)
+	out := make(chan BatchResult, 1)
+	require.NoError(t, (&BatchRunner{}).RunSink(context.Background(), reqs, ChanSink(out)))
+	require.Equal(t, BatchCompleted, (<-out).Status)
+}
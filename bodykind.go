@@ -0,0 +1,97 @@
+package gcurl
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// BodyKind classifies a request body so converters can pick a fitting
+// representation.
+type BodyKind string
+
+const (
+	BodyNone    BodyKind = ""
+	BodyJSON    BodyKind = "json"
+	BodyForm    BodyKind = "form"
+	BodyXML     BodyKind = "xml"
+	BodyGraphQL BodyKind = "graphql"
+	BodyBinary  BodyKind = "binary"
+	BodyText    BodyKind = "text"
+	// BodyMultipart is set for requests with a Form.
+	BodyMultipart BodyKind = "multipart"
+)
+
+var formBodyRe = regexp.MustCompile(`^[^=&\s]+=[^&\s]*(&[^=&\s]+=[^&\s]*)*$`)
+
+// DetectBodyKind returns the kind of body. The content type decides when
+// it is known, otherwise the body is sniffed. JSON bodies carrying a
+// GraphQL query are reported as BodyGraphQL.
+func DetectBodyKind(contentType, body string) BodyKind {
+	if body == "" {
+		return BodyNone
+	}
+	if kind := contentTypeKind(contentType, body); kind != BodyNone {
+		return kind
+	}
+	return sniffBodyKind(body)
+}
+
+// contentTypeKind returns the kind implied by contentType, or BodyNone
+// if the body has to be sniffed.
+func contentTypeKind(contentType, body string) BodyKind {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mt == "application/graphql":
+		return BodyGraphQL
+	case mt == ContentTypeJSON || strings.HasSuffix(mt, "+json"):
+		if isGraphQLBody(body) {
+			return BodyGraphQL
+		}
+		return BodyJSON
+	case mt == ContentTypeForm:
+		return BodyForm
+	case mt == "application/xml" || mt == "text/xml" || strings.HasSuffix(mt, "+xml"):
+		return BodyXML
+	case strings.HasPrefix(mt, "text/"):
+		return BodyText
+	case mt == "application/octet-stream" || strings.HasPrefix(mt, "image/") ||
+		strings.HasPrefix(mt, "audio/") || strings.HasPrefix(mt, "video/"):
+		return BodyBinary
+	}
+	return BodyNone
+}
+
+func sniffBodyKind(body string) BodyKind {
+	if !utf8.ValidString(body) || strings.ContainsRune(body, 0) {
+		return BodyBinary
+	}
+
+	trimmed := strings.TrimSpace(body)
+	switch {
+	case (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)):
+		if isGraphQLBody(trimmed) {
+			return BodyGraphQL
+		}
+		return BodyJSON
+	case strings.HasPrefix(trimmed, "<") && strings.HasSuffix(trimmed, ">"):
+		return BodyXML
+	case formBodyRe.MatchString(trimmed):
+		return BodyForm
+	}
+	return BodyText
+}
+
+// isGraphQLBody reports whether body is a GraphQL-over-HTTP payload: a
+// JSON object with a string query.
+func isGraphQLBody(body string) bool {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		return false
+	}
+	query, ok := payload["query"]
+	return ok && bytes.HasPrefix(bytes.TrimSpace(query), []byte(`"`))
+}
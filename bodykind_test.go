@@ -0,0 +1,64 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectBodyKind(t *testing.T) {
+	var tests = []struct {
+		name        string
+		contentType string
+		body        string
+		expected    BodyKind
+	}{
+		{"empty", ContentTypeJSON, "", BodyNone},
+		{"json content type", "application/json; charset=utf-8", `{"a":1}`, BodyJSON},
+		{"vendor json", "application/vnd.api+json", `{"a":1}`, BodyJSON},
+		{"graphql over json", ContentTypeJSON, `{"query":"{ sloths { name } }","variables":{}}`, BodyGraphQL},
+		{"graphql content type", "application/graphql", `{ sloths { name } }`, BodyGraphQL},
+		{"form content type", ContentTypeForm, "a=1&b=2", BodyForm},
+		{"xml content type", "text/xml", "<a/>", BodyXML},
+		{"soap", "application/soap+xml", "<a/>", BodyXML},
+		{"text content type", "text/plain", `{"a":1}`, BodyText},
+		{"octet stream", "application/octet-stream", "hello", BodyBinary},
+		{"sniff json", "", ` [1, 2] `, BodyJSON},
+		{"sniff graphql", "", `{"query":"{ sloths }"}`, BodyGraphQL},
+		{"sniff query field is not graphql", "", `{"query":{"name":"sid"}}`, BodyJSON},
+		{"sniff xml", "", `<?xml version="1.0"?><a/>`, BodyXML},
+		{"sniff form", "", "name=sid&tags=a%20b&empty=", BodyForm},
+		{"sniff binary", "", "\x89PNG\x00\x01", BodyBinary},
+		{"sniff invalid json", "", `{"a":`, BodyText},
+		{"sniff text", "", "hello world", BodyText},
+		{"unknown content type sniffs", "application/x-custom", `{"a":1}`, BodyJSON},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, DetectBodyKind(tt.contentType, tt.body))
+		})
+	}
+}
+
+func TestParseBodyKind(t *testing.T) {
+	var tests = []struct {
+		curl        string
+		expected    BodyKind
+		contentType string
+	}{
+		{`curl -d '{"name":"sid"}' https://api.site.com`, BodyJSON, ContentTypeForm},
+		{`curl -H 'Content-Type: text/plain' -d '{"name":"sid"}' https://api.site.com`, BodyText, "text/plain"},
+		{`curl -d 'name=sid' https://api.site.com`, BodyForm, ContentTypeForm},
+		{`curl -d '' https://api.site.com`, BodyNone, ContentTypeForm},
+		{`curl https://api.site.com`, BodyNone, ""},
+	}
+
+	for _, tt := range tests {
+		actual, err := Parse(tt.curl)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, actual.BodyKind, tt.curl)
+		require.Equal(t, tt.contentType, actual.Header[KeyContentType], tt.curl)
+	}
+}
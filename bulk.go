@@ -0,0 +1,197 @@
+package gcurl
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// BulkParser parses large corpora of curl commands (shell histories, access
+// logs, scraped scripts) across a pool of workers, streaming results as
+// they become available. Results are not guaranteed to keep input order.
+type BulkParser struct {
+	// Workers is the number of parsing goroutines, runtime.NumCPU() if zero.
+	Workers int
+	// Logger, if set, receives a structured record per command with its
+	// hash, host, method, flags and parse error. Combined with
+	// slog.NewJSONHandler it produces logs ready for ELK or Loki.
+	Logger *slog.Logger
+}
+
+type BulkResult struct {
+	// Line is the 1-based input line (or item index for channels) the
+	// command started on.
+	Line    int
+	Command string
+	Request *Request
+	Err     error
+}
+
+type bulkJob struct {
+	line int
+	cmd  string
+	// err is a read error, passed on as the result.
+	err error
+}
+
+// ParseReader scans r line by line, extracting curl invocations from lines
+// that contain one. Lines ending with a backslash are joined with the next.
+// A read error, including a line over 10 MB, ends the scan and is sent as
+// a last result with Err set and no Command.
+func (b *BulkParser) ParseReader(ctx context.Context, r io.Reader) <-chan BulkResult {
+	jobs := make(chan bulkJob)
+	out := b.run(ctx, jobs)
+
+	go func() {
+		defer close(jobs)
+
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		var pending []string
+		var start, line int
+		for scanner.Scan() {
+			line++
+			text := strings.TrimRight(scanner.Text(), "\r")
+			if pending == nil {
+				cmd, ok := extractCurl(text)
+				if !ok {
+					continue
+				}
+				text, start = cmd, line
+			}
+
+			pending = append(pending, text)
+			if strings.HasSuffix(text, `\`) {
+				continue
+			}
+			if !b.send(ctx, jobs, bulkJob{line: start, cmd: strings.Join(pending, "\n")}) {
+				return
+			}
+			pending = nil
+		}
+		if pending != nil {
+			if !b.send(ctx, jobs, bulkJob{line: start, cmd: strings.Join(pending, "\n")}) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			b.send(ctx, jobs, bulkJob{line: line + 1, err: fmt.Errorf("bulk: read line %d: %w", line+1, err)})
+		}
+	}()
+	return out
+}
+
+// ParseChan parses every command received on in until it is closed.
+func (b *BulkParser) ParseChan(ctx context.Context, in <-chan string) <-chan BulkResult {
+	jobs := make(chan bulkJob)
+	out := b.run(ctx, jobs)
+
+	go func() {
+		defer close(jobs)
+
+		line := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case cmd, ok := <-in:
+				if !ok {
+					return
+				}
+				line++
+				if !b.send(ctx, jobs, bulkJob{line: line, cmd: cmd}) {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+func (b *BulkParser) send(ctx context.Context, jobs chan<- bulkJob, job bulkJob) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case jobs <- job:
+		return true
+	}
+}
+
+func (b *BulkParser) run(ctx context.Context, jobs <-chan bulkJob) <-chan BulkResult {
+	workers := b.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	out := make(chan BulkResult, workers)
+	wg := &sync.WaitGroup{}
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				res := BulkResult{Line: job.line, Command: job.cmd, Err: job.err}
+				if job.err == nil {
+					res.Request, res.Err = ParseContext(ctx, job.cmd)
+				}
+				if b.Logger != nil {
+					b.log(ctx, res)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- res:
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+func (b *BulkParser) log(ctx context.Context, res BulkResult) {
+	sum := sha256.Sum256([]byte(res.Command))
+	attrs := []slog.Attr{
+		slog.Int("line", res.Line),
+		slog.String("command_hash", hex.EncodeToString(sum[:])),
+		slog.Any("flags", commandFlags(res.Command)),
+	}
+	if res.Err != nil {
+		attrs = append(attrs, slog.String("error", res.Err.Error()))
+		b.Logger.LogAttrs(ctx, slog.LevelWarn, "curl parse failed", attrs...)
+		return
+	}
+	attrs = append(attrs,
+		slog.String("host", requestHost(res.Request)),
+		slog.String("method", res.Request.Method),
+		slog.String("fingerprint", res.Request.Fingerprint()),
+	)
+	b.Logger.LogAttrs(ctx, slog.LevelInfo, "curl parsed", attrs...)
+}
+
+// extractCurl returns the curl invocation embedded in a history or log
+// line, e.g. zsh's ": 1700000000:0;curl ..." or bash's "  42  curl ...".
+func extractCurl(line string) (string, bool) {
+	for i := 0; ; {
+		idx := strings.Index(line[i:], "curl ")
+		if idx < 0 {
+			return "", false
+		}
+		idx += i
+		if idx == 0 || strings.ContainsRune(" \t;:|&(", rune(line[idx-1])) {
+			return line[idx:], true
+		}
+		i = idx + 1
+	}
+}
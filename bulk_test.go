@@ -0,0 +1,134 @@
+package gcurl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectBulk(results <-chan BulkResult) []BulkResult {
+	var all []BulkResult
+	for res := range results {
+		all = append(all, res)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Line < all[j].Line })
+	return all
+}
+
+func TestBulkParserParseReader(t *testing.T) {
+	given := ": 1700000000:0;curl https://api.site.com/a\n" +
+		"ls -la\n" +
+		"  42  curl -X DELETE https://api.site.com/b\n" +
+		"curl -H 'Accept: text/plain' \\\n" +
+		"  https://api.site.com/c\n" +
+		"echo curly\n" +
+		"curl -H 'unterminated https://api.site.com/d\n"
+
+	b := &BulkParser{Workers: 3}
+	all := collectBulk(b.ParseReader(context.Background(), strings.NewReader(given)))
+	require.Len(t, all, 4)
+
+	require.Equal(t, 1, all[0].Line)
+	require.Equal(t, "https://api.site.com/a", all[0].Request.URL)
+	require.Equal(t, 3, all[1].Line)
+	require.Equal(t, "DELETE", all[1].Request.Method)
+	require.Equal(t, 4, all[2].Line)
+	require.Equal(t, "https://api.site.com/c", all[2].Request.URL)
+	require.Equal(t, "text/plain", all[2].Request.Header["accept"])
+	require.Equal(t, 7, all[3].Line)
+	require.Error(t, all[3].Err)
+}
+
+func TestBulkParserReadError(t *testing.T) {
+	readErr := errors.New("disk failure")
+	var tests = []struct {
+		name string
+		r    io.Reader
+		err  error
+	}{
+		{"reader", io.MultiReader(strings.NewReader("curl https://api.site.com/a\n"), iotest.ErrReader(readErr)), readErr},
+		{"line too long", strings.NewReader("curl https://api.site.com/a\n" + strings.Repeat("x", 11*1024*1024) + "\n"), bufio.ErrTooLong},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			b := &BulkParser{Workers: 2}
+			all := collectBulk(b.ParseReader(context.Background(), tt.r))
+			require.Len(t, all, 2)
+			require.NoError(t, all[0].Err)
+			require.Equal(t, "https://api.site.com/a", all[0].Request.URL)
+			require.Equal(t, 2, all[1].Line)
+			require.ErrorIs(t, all[1].Err, tt.err)
+			require.Nil(t, all[1].Request)
+		})
+	}
+}
+
+func TestBulkParserLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	b := &BulkParser{Workers: 1, Logger: slog.New(slog.NewJSONHandler(buf, nil))}
+	given := "curl -s -X POST -H 'Accept: */*' --compressed https://API.site.com/a\n" +
+		"curl -H 'unterminated https://api.site.com/b\n"
+	collectBulk(b.ParseReader(context.Background(), strings.NewReader(given)))
+
+	var records []map[string]interface{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var rec map[string]interface{}
+		require.NoError(t, dec.Decode(&rec))
+		delete(rec, "time")
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i]["line"].(float64) < records[j]["line"].(float64) })
+	require.Len(t, records, 2)
+
+	require.Equal(t, "INFO", records[0]["level"])
+	require.Equal(t, "curl parsed", records[0]["msg"])
+	require.Equal(t, "api.site.com", records[0]["host"])
+	require.Equal(t, "POST", records[0]["method"])
+	require.Equal(t, []interface{}{"-s", "-X", "-H", "--compressed"}, records[0]["flags"])
+	require.Len(t, records[0]["command_hash"], 64)
+	require.Len(t, records[0]["fingerprint"], 64)
+
+	require.Equal(t, "WARN", records[1]["level"])
+	require.Equal(t, "curl parse failed", records[1]["msg"])
+	require.NotEmpty(t, records[1]["error"])
+	require.NotContains(t, records[1], "host")
+}
+
+func TestBulkParserParseChan(t *testing.T) {
+	in := make(chan string)
+	go func() {
+		defer close(in)
+		for i := 0; i < 100; i++ {
+			in <- "curl https://api.site.com/sloths"
+		}
+	}()
+
+	all := collectBulk((&BulkParser{}).ParseChan(context.Background(), in))
+	require.Len(t, all, 100)
+	for i, res := range all {
+		require.Equal(t, i+1, res.Line)
+		require.NoError(t, res.Err)
+	}
+}
+
+func TestBulkParserCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	in := make(chan string)
+	all := collectBulk((&BulkParser{Workers: 2}).ParseChan(ctx, in))
+	require.Empty(t, all)
+}
@@ -0,0 +1,268 @@
+package gcurl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCache stores responses by request fingerprint. Implementations
+// must be safe for concurrent use.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse)
+}
+
+// CachedResponse is a response kept by a ResponseCache.
+type CachedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	// Stored is when the response was received or last revalidated.
+	Stored time.Time `json:"stored"`
+}
+
+// WithResponseCache serves GET and HEAD requests from cache while the
+// cached response is fresh per its Cache-Control max-age, and otherwise
+// revalidates it with its ETag and Last-Modified, so that replaying a
+// catalog only transfers what changed. Responses marked no-store, and
+// requests that are already conditional or send Cache-Control no-store,
+// bypass the cache.
+func WithResponseCache(c ResponseCache) ExecOption {
+	return func(cfg *execConfig) { cfg.cache = c }
+}
+
+// cachedSend returns a send function going through the cache for req.
+// Entries are keyed by the outgoing request, as rewritten.
+func (c *execConfig) cachedSend(req *Request) func(*http.Client, *http.Request) (*Response, error) {
+	if c.cache == nil || !cacheableRequest(req) {
+		return send
+	}
+	return func(client *http.Client, hreq *http.Request) (*Response, error) {
+		key, err := cacheKey(hreq)
+		if err != nil {
+			return nil, err
+		}
+		entry, ok := c.cache.Get(key)
+		if ok && entry.fresh(c.now()) {
+			return entry.response(hreq), nil
+		}
+		if ok {
+			v := Validators{ETag: entry.Header.Get("ETag"), LastModified: entry.Header.Get("Last-Modified")}
+			if v.ETag != "" {
+				hreq.Header.Set("If-None-Match", v.ETag)
+			}
+			if v.LastModified != "" {
+				hreq.Header.Set("If-Modified-Since", v.LastModified)
+			}
+		}
+
+		resp, err := send(client, hreq)
+		if err != nil {
+			return nil, err
+		}
+		if ok && resp.NotModified() {
+			updated := *entry
+			updated.Header = entry.Header.Clone()
+			for k, vals := range resp.Header {
+				if k != "Content-Length" {
+					updated.Header[k] = vals
+				}
+			}
+			updated.Stored = c.now()
+			c.cache.Set(key, &updated)
+			return updated.response(hreq), nil
+		}
+		if storable(resp) {
+			c.cache.Set(key, &CachedResponse{
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header.Clone(),
+				Body:       resp.body,
+				Stored:     c.now(),
+			})
+		}
+		return resp, nil
+	}
+}
+
+// cacheKey hashes the method, URL, headers and body of the outgoing
+// request.
+func cacheKey(hreq *http.Request) (string, error) {
+	body, err := requestBody(hreq)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	write := func(s string) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+
+	write(hreq.Method)
+	write(hreq.URL.String())
+	write(hreq.Host)
+	names := make([]string, 0, len(hreq.Header))
+	for k := range hreq.Header {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		write(k)
+		for _, v := range hreq.Header[k] {
+			write(v)
+		}
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func cacheableRequest(req *Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+	default:
+		return false
+	}
+	if !req.Conditional().IsZero() {
+		return false
+	}
+	_, noStore := cacheControl(req.Header.Get("cache-control"))["no-store"]
+	return !noStore
+}
+
+func storable(resp *Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	cc := cacheControl(resp.Header.Get("Cache-Control"))
+	if _, ok := cc["no-store"]; ok {
+		return false
+	}
+	_, hasMaxAge := cc["max-age"]
+	return hasMaxAge || !resp.Validators().IsZero()
+}
+
+func (e *CachedResponse) fresh(now time.Time) bool {
+	cc := cacheControl(e.Header.Get("Cache-Control"))
+	if _, ok := cc["no-cache"]; ok {
+		return false
+	}
+	maxAge, err := strconv.Atoi(cc["max-age"])
+	if err != nil || maxAge <= 0 {
+		return false
+	}
+	return now.Before(e.Stored.Add(time.Duration(maxAge) * time.Second))
+}
+
+func (e *CachedResponse) response(hreq *http.Request) *Response {
+	return &Response{
+		Response: &http.Response{
+			Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+			StatusCode:    e.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        e.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(e.Body)),
+			ContentLength: int64(len(e.Body)),
+			Request:       hreq,
+		},
+		body: e.Body,
+	}
+}
+
+// cacheControl parses Cache-Control directives, lower-casing their names.
+func cacheControl(header string) map[string]string {
+	cc := map[string]string{}
+	for _, d := range strings.Split(header, ",") {
+		name, val, _ := strings.Cut(strings.TrimSpace(d), "=")
+		if name != "" {
+			cc[strings.ToLower(name)] = strings.Trim(val, `"`)
+		}
+	}
+	return cc
+}
+
+// MemoryCache is a ResponseCache held in memory.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*CachedResponse
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]*CachedResponse{}}
+}
+
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+func (c *MemoryCache) Set(key string, resp *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = resp
+}
+
+// DiskCache is a ResponseCache storing one JSON file per response in Dir,
+// so that it survives between runs. Entries that can't be read or written
+// are treated as missing.
+type DiskCache struct {
+	Dir string
+}
+
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{Dir: dir}
+}
+
+func (c *DiskCache) Get(key string) (*CachedResponse, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var e CachedResponse
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+func (c *DiskCache) Set(key string, resp *CachedResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return
+	}
+	// Write then rename, so that concurrent readers never see a partial file.
+	tmp, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
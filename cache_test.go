@@ -0,0 +1,112 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	var hits, notModified int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/etag":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("ETag", `"v1"`)
+		}
+		w.Write([]byte("sloths at " + r.URL.Path))
+	}))
+	defer srv.Close()
+
+	caches := map[string]ResponseCache{
+		"memory": NewMemoryCache(),
+		"disk":   NewDiskCache(t.TempDir()),
+	}
+	for name, cache := range caches {
+		cache := cache
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&hits, 0)
+			atomic.StoreInt32(&notModified, 0)
+			get := func(cmd string) *Response {
+				resp, err := Do(context.Background(), cmd, WithResponseCache(cache))
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				return resp
+			}
+
+			for i := 0; i < 3; i++ {
+				require.Equal(t, "sloths at /fresh", get("curl "+srv.URL+"/fresh").Text())
+			}
+			require.EqualValues(t, 1, atomic.LoadInt32(&hits))
+
+			for i := 0; i < 3; i++ {
+				require.Equal(t, "sloths at /etag", get("curl "+srv.URL+"/etag").Text())
+			}
+			require.EqualValues(t, 4, atomic.LoadInt32(&hits))
+			require.EqualValues(t, 2, atomic.LoadInt32(&notModified))
+
+			get("curl " + srv.URL + "/no-store")
+			get("curl " + srv.URL + "/no-store")
+			get("curl -d a=1 " + srv.URL + "/fresh")
+			get("curl -H 'Cache-Control: no-store' " + srv.URL + "/fresh")
+			require.EqualValues(t, 8, atomic.LoadInt32(&hits))
+		})
+	}
+}
+
+func TestResponseCacheRewrite(t *testing.T) {
+	serve := func(body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Write([]byte(body))
+		}))
+	}
+	prod, staging := serve("prod"), serve("staging")
+	defer prod.Close()
+	defer staging.Close()
+
+	cache := NewMemoryCache()
+	cmd := "curl " + prod.URL + "/sloths"
+	resp, err := Do(context.Background(), cmd, WithResponseCache(cache))
+	require.NoError(t, err)
+	require.Equal(t, "prod", resp.Text())
+
+	resp, err = Do(context.Background(), cmd, WithResponseCache(cache),
+		WithHostRewrite(strings.TrimPrefix(prod.URL, "http://"), strings.TrimPrefix(staging.URL, "http://")))
+	require.NoError(t, err)
+	require.Equal(t, "staging", resp.Text())
+
+	resp, err = Do(context.Background(), cmd, WithResponseCache(cache), WithHeaderRules(SetHeader("X-Replay", "true")))
+	require.NoError(t, err)
+	require.Equal(t, "prod", resp.Text())
+	require.Len(t, cache.entries, 3)
+}
+
+func TestCachedResponseFresh(t *testing.T) {
+	now := time.Now()
+	entry := func(cc string, age time.Duration) *CachedResponse {
+		return &CachedResponse{Header: http.Header{"Cache-Control": {cc}}, Stored: now.Add(-age)}
+	}
+
+	require.True(t, entry("public, max-age=60", 30*time.Second).fresh(now))
+	require.False(t, entry("max-age=60", 90*time.Second).fresh(now))
+	require.False(t, entry("max-age=60, no-cache", 0).fresh(now))
+	require.False(t, entry("", 0).fresh(now))
+}
@@ -0,0 +1,243 @@
+package gcurl
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// canonicalVersion prefixes the binary encoding so it can evolve.
+const canonicalVersion = 1
+
+var errCanonicalTruncated = errors.New("binary request: truncated")
+
+// MarshalBinary encodes the request canonically: fields in a fixed order,
+// headers sorted by name and strings length-prefixed, so equal requests
+// always give the same bytes. It is meant for cache keys and
+// content-addressed storage. Extensions are encoded as JSON, whose
+// object keys are sorted. Parse diagnostics, Unrecognized and Warnings,
+// are left out.
+func (r *Request) MarshalBinary() ([]byte, error) {
+	var b []byte
+	str := func(s string) {
+		b = binary.AppendUvarint(b, uint64(len(s)))
+		b = append(b, s...)
+	}
+
+	b = append(b, canonicalVersion)
+	str(r.Method)
+	str(r.URL)
+	b = binary.AppendUvarint(b, uint64(len(r.Header)))
+	for _, k := range sortedKeys(r.Header) {
+		str(k)
+		str(r.Header[k])
+	}
+	str(r.Body)
+	str(r.BodyFile)
+	b = append(b, boolByte(r.SkipTLS))
+	str(r.Timeout)
+	str(string(r.BodyKind))
+	b = binary.AppendUvarint(b, uint64(len(r.Form)))
+	for _, f := range r.Form {
+		str(f.Name)
+		str(f.Value)
+		str(f.File)
+		str(f.Filename)
+		str(f.ContentType)
+	}
+
+	multi := make([]string, 0, len(r.MultiHeader))
+	for k := range r.MultiHeader {
+		multi = append(multi, k)
+	}
+	sort.Strings(multi)
+	b = binary.AppendUvarint(b, uint64(len(multi)))
+	for _, k := range multi {
+		str(k)
+		b = binary.AppendUvarint(b, uint64(len(r.MultiHeader[k])))
+		for _, v := range r.MultiHeader[k] {
+			str(v)
+		}
+	}
+
+	str(r.Proxy)
+	str(r.ProxyAuth)
+	b = binary.AppendUvarint(b, uint64(len(r.NoProxy)))
+	for _, h := range r.NoProxy {
+		str(h)
+	}
+	b = binary.AppendUvarint(b, uint64(r.URLEncoding))
+	b = append(b, boolByte(r.FollowRedirects), boolByte(r.TrustRedirects))
+	b = binary.AppendVarint(b, int64(r.MaxRedirects))
+	str(r.UploadFile)
+	b = binary.AppendVarint(b, int64(r.ConnectTimeout))
+	str(r.CookieFile)
+	str(r.CookieJarFile)
+	b = append(b, boolByte(r.AutoReferer), boolByte(r.Compressed))
+	b = append(b, boolByte(r.Auth != nil))
+	if r.Auth != nil {
+		str(string(r.Auth.Type))
+		str(r.Auth.Username)
+		str(r.Auth.Password)
+		str(r.Auth.Token)
+		str(r.Auth.AWSSigV4)
+	}
+	for _, f := range r.Form {
+		b = binary.AppendUvarint(b, uint64(len(f.Headers)))
+		for _, h := range f.Headers {
+			str(h)
+		}
+	}
+
+	var ext []byte
+	if len(r.Extensions) > 0 {
+		var err error
+		if ext, err = json.Marshal(r.Extensions); err != nil {
+			return nil, fmt.Errorf("binary request: extensions: %w", err)
+		}
+	}
+	str(string(ext))
+	return b, nil
+}
+
+// UnmarshalBinary decodes a request encoded by MarshalBinary. Extension
+// values come back as their JSON decoding.
+func (r *Request) UnmarshalBinary(data []byte) error {
+	d := &canonicalDecoder{b: data}
+	if v := d.byte(); d.err == nil && v != canonicalVersion {
+		return fmt.Errorf("binary request: unsupported version %d", v)
+	}
+
+	res := Request{Method: d.str(), URL: d.str()}
+	if n := d.uvarint(); n > 0 {
+		res.Header = make(Header, min(n, uint64(len(data))))
+		for i := uint64(0); i < n && d.err == nil; i++ {
+			k := d.str()
+			res.Header[k] = d.str()
+		}
+	} else {
+		res.Header = Header{}
+	}
+	res.Body = d.str()
+	res.BodyFile = d.str()
+	res.SkipTLS = d.byte() == 1
+	res.Timeout = d.str()
+	res.BodyKind = BodyKind(d.str())
+	for n, i := d.uvarint(), uint64(0); i < n && d.err == nil; i++ {
+		res.Form = append(res.Form, FormField{
+			Name: d.str(), Value: d.str(), File: d.str(), Filename: d.str(), ContentType: d.str(),
+		})
+	}
+	for n, i := d.uvarint(), uint64(0); i < n && d.err == nil; i++ {
+		if res.MultiHeader == nil {
+			res.MultiHeader = http.Header{}
+		}
+		k := d.str()
+		for m, j := d.uvarint(), uint64(0); j < m && d.err == nil; j++ {
+			res.MultiHeader[k] = append(res.MultiHeader[k], d.str())
+		}
+	}
+	res.Proxy = d.str()
+	res.ProxyAuth = d.str()
+	for n, i := d.uvarint(), uint64(0); i < n && d.err == nil; i++ {
+		res.NoProxy = append(res.NoProxy, d.str())
+	}
+	res.URLEncoding = URLEncoding(d.uvarint())
+	res.FollowRedirects = d.byte() == 1
+	res.TrustRedirects = d.byte() == 1
+	res.MaxRedirects = int(d.varint())
+	res.UploadFile = d.str()
+	res.ConnectTimeout = time.Duration(d.varint())
+	res.CookieFile = d.str()
+	res.CookieJarFile = d.str()
+	res.AutoReferer = d.byte() == 1
+	res.Compressed = d.byte() == 1
+	if d.byte() == 1 {
+		res.Auth = &Auth{Type: AuthType(d.str()), Username: d.str(), Password: d.str(), Token: d.str(), AWSSigV4: d.str()}
+	}
+	for i := 0; i < len(res.Form) && d.err == nil; i++ {
+		for n, j := d.uvarint(), uint64(0); j < n && d.err == nil; j++ {
+			res.Form[i].Headers = append(res.Form[i].Headers, d.str())
+		}
+	}
+	ext := d.str()
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.b) > 0 {
+		return fmt.Errorf("binary request: %d trailing bytes", len(d.b))
+	}
+	if ext != "" {
+		if err := json.Unmarshal([]byte(ext), &res.Extensions); err != nil {
+			return fmt.Errorf("binary request: extensions: %w", err)
+		}
+	}
+	*r = res
+	return nil
+}
+
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+type canonicalDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *canonicalDecoder) byte() byte {
+	if d.err != nil || len(d.b) == 0 {
+		d.err = errCanonicalTruncated
+		return 0
+	}
+	c := d.b[0]
+	d.b = d.b[1:]
+	return c
+}
+
+func (d *canonicalDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errCanonicalTruncated
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *canonicalDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = errCanonicalTruncated
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *canonicalDecoder) str() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if n > uint64(len(d.b)) {
+		d.err = errCanonicalTruncated
+		return ""
+	}
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
@@ -0,0 +1,55 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBinaryRoundTrip(t *testing.T) {
+	req, err := Parse(`curl -k -m 3 --connect-timeout 1.5 --location-trusted --max-redirs -1 -H 'X-B: 2' -H 'X-A: 1' -F name=sid -F 'avatar=@/tmp/sid.png;type=image/png;headers=X-Sloth: sid' https://api.site.com/sloths`)
+	require.NoError(t, err)
+	req.SetExtension("trace", map[string]interface{}{"b": "2", "a": true})
+	req.URLEncoding = URLEncodingPreserve
+
+	data, err := req.MarshalBinary()
+	require.NoError(t, err)
+
+	// Encoding is stable across map iteration orders.
+	for i := 0; i < 20; i++ {
+		again, err := req.Clone().MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, data, again)
+	}
+
+	decoded := &Request{}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, req, decoded)
+
+	other := req.Clone()
+	other.Header["x-a"] = "12"
+	otherData, err := other.MarshalBinary()
+	require.NoError(t, err)
+	require.NotEqual(t, data, otherData)
+}
+
+func TestRequestBinaryEmpty(t *testing.T) {
+	req := &Request{Method: "GET", URL: "https://api.site.com", Header: Header{}}
+	data, err := req.MarshalBinary()
+	require.NoError(t, err)
+
+	decoded := &Request{}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, req, decoded)
+}
+
+func TestRequestBinaryInvalid(t *testing.T) {
+	data, err := (&Request{Method: "GET", URL: "https://api.site.com", Header: Header{"a": "b"}}).MarshalBinary()
+	require.NoError(t, err)
+
+	for i := 0; i < len(data); i++ {
+		require.Error(t, (&Request{}).UnmarshalBinary(data[:i]), i)
+	}
+	require.Error(t, (&Request{}).UnmarshalBinary(append(data, 0)))
+	require.Error(t, (&Request{}).UnmarshalBinary(append([]byte{9}, data[1:]...)))
+}
@@ -0,0 +1,225 @@
+package gcurl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CatalogEntry is a named, tagged request stored in a Catalog.
+type CatalogEntry struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// Schedule, if set, runs the request periodically with a CronRunner.
+	// See ParseSchedule for the syntax.
+	Schedule string   `json:"schedule,omitempty"`
+	Request  *Request `json:"request"`
+}
+
+// HasTag reports whether the entry is tagged with tag.
+func (e *CatalogEntry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Catalog is a library of named requests that can be persisted as JSON or
+// YAML. Stored requests may contain {{name}} placeholders filled in by
+// Render, or by RenderEnv together with an environment profile. A Catalog
+// is safe for concurrent use.
+type Catalog struct {
+	mu           sync.RWMutex
+	entries      map[string]*CatalogEntry
+	environments map[string]*Environment
+}
+
+type catalogFile struct {
+	Entries      []*CatalogEntry `json:"entries"`
+	Environments []*Environment  `json:"environments,omitempty"`
+}
+
+func NewCatalog() *Catalog {
+	return &Catalog{entries: map[string]*CatalogEntry{}}
+}
+
+// Add stores e, failing if an entry with the same name exists.
+func (c *Catalog) Add(e *CatalogEntry) error {
+	if e.Name == "" {
+		return fmt.Errorf("catalog: entry without name")
+	}
+	if e.Request == nil {
+		return fmt.Errorf("catalog: entry %q without request", e.Name)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[e.Name]; ok {
+		return fmt.Errorf("catalog: entry %q already exists", e.Name)
+	}
+	if c.entries == nil {
+		c.entries = map[string]*CatalogEntry{}
+	}
+	c.entries[e.Name] = e
+	return nil
+}
+
+// Remove deletes the named entry, reporting whether it existed.
+func (c *Catalog) Remove(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[name]
+	delete(c.entries, name)
+	return ok
+}
+
+func (c *Catalog) Get(name string) (*CatalogEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.entries[name]
+	return e, ok
+}
+
+// Entries returns all entries sorted by name.
+func (c *Catalog) Entries() []*CatalogEntry {
+	return c.Tagged()
+}
+
+// Tagged returns the entries carrying all the given tags, sorted by name.
+func (c *Catalog) Tagged(tags ...string) []*CatalogEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var res []*CatalogEntry
+entries:
+	for _, e := range c.entries {
+		for _, tag := range tags {
+			if !e.HasTag(tag) {
+				continue entries
+			}
+		}
+		res = append(res, e)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// Render returns a copy of the named request with its {{name}}
+// placeholders replaced by vars. Unknown placeholders are left untouched.
+func (c *Catalog) Render(name string, vars map[string]string) (*Request, error) {
+	e, ok := c.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("catalog: no entry %q", name)
+	}
+	return renderRequest(e.Request, vars), nil
+}
+
+func renderRequest(req *Request, vars map[string]string) *Request {
+	res := req.Clone()
+	res.URL = expandPlaceholders(res.URL, vars)
+	res.Body = expandPlaceholders(res.Body, vars)
+	for k, v := range res.Header {
+		res.Header[k] = expandPlaceholders(v, vars)
+	}
+	return res
+}
+
+// WriteJSON writes the catalog as indented JSON.
+func (c *Catalog) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(catalogFile{Entries: c.Entries(), Environments: c.Environments()})
+}
+
+// WriteYAML writes the catalog as YAML, using the same field names as the
+// JSON form.
+func (c *Catalog) WriteYAML(w io.Writer) error {
+	buf := &bytes.Buffer{}
+	if err := c.WriteJSON(buf); err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := yaml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// ReadCatalog reads a catalog written by WriteJSON or WriteYAML.
+func ReadCatalog(r io.Reader) (*Catalog, error) {
+	// YAML is a superset of JSON, so both go through the YAML decoder and
+	// are then mapped onto the JSON field names.
+	var doc interface{}
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("catalog: %w", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("catalog: %w", err)
+	}
+
+	var file catalogFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("catalog: %w", err)
+	}
+	c := NewCatalog()
+	for _, e := range file.Entries {
+		if e.Request != nil && e.Request.Header == nil {
+			e.Request.Header = Header{}
+		}
+		if err := c.Add(e); err != nil {
+			return nil, err
+		}
+	}
+	for _, env := range file.Environments {
+		if err := c.AddEnvironment(env); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// LoadCatalog reads a catalog file in JSON or YAML.
+func LoadCatalog(path string) (*Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCatalog(f)
+}
+
+// Save writes the catalog to path, as YAML for .yaml and .yml files and
+// as JSON otherwise.
+func (c *Catalog) Save(path string) error {
+	buf := &bytes.Buffer{}
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = c.WriteYAML(buf)
+	default:
+		err = c.WriteJSON(buf)
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
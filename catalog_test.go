@@ -0,0 +1,109 @@
+package gcurl
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCatalog(t *testing.T) *Catalog {
+	c := NewCatalog()
+	for _, e := range []struct {
+		name string
+		tags []string
+		curl string
+	}{
+		{"get-sloth", []string{"sloths", "read"}, `curl -H 'Authorization: Bearer {{token}}' 'https://{{host}}/sloths/{{id}}'`},
+		{"create-sloth", []string{"sloths"}, `curl -H 'Content-Type: application/json' -d '{"name":"{{name}}"}' 'https://api.site.com/sloths'`},
+		{"health", []string{"ops", "read"}, `curl https://api.site.com/health`},
+	} {
+		req, err := Parse(e.curl)
+		require.NoError(t, err)
+		require.NoError(t, c.Add(&CatalogEntry{Name: e.name, Tags: e.tags, Request: req}))
+	}
+	return c
+}
+
+func entryNames(entries []*CatalogEntry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func TestCatalog(t *testing.T) {
+	c := newTestCatalog(t)
+
+	require.Equal(t, []string{"create-sloth", "get-sloth", "health"}, entryNames(c.Entries()))
+	require.Equal(t, []string{"get-sloth", "health"}, entryNames(c.Tagged("read")))
+	require.Equal(t, []string{"get-sloth"}, entryNames(c.Tagged("read", "sloths")))
+	require.Empty(t, c.Tagged("missing"))
+
+	e, ok := c.Get("health")
+	require.True(t, ok)
+	require.Equal(t, "https://api.site.com/health", e.Request.URL)
+
+	require.Error(t, c.Add(&CatalogEntry{Name: "health", Request: e.Request}))
+	require.Error(t, c.Add(&CatalogEntry{Request: e.Request}))
+	require.Error(t, c.Add(&CatalogEntry{Name: "empty"}))
+
+	require.True(t, c.Remove("health"))
+	require.False(t, c.Remove("health"))
+	_, ok = c.Get("health")
+	require.False(t, ok)
+}
+
+func TestCatalogRender(t *testing.T) {
+	c := newTestCatalog(t)
+
+	req, err := c.Render("get-sloth", map[string]string{"host": "api.site.com", "id": "4", "token": "t0k"})
+	require.NoError(t, err)
+	require.Equal(t, "https://api.site.com/sloths/4", req.URL)
+	require.Equal(t, "Bearer t0k", req.Header[KeyAuthorization])
+
+	req, err = c.Render("create-sloth", map[string]string{})
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"{{name}}"}`, req.Body)
+
+	e, _ := c.Get("get-sloth")
+	require.Equal(t, "https://{{host}}/sloths/{{id}}", e.Request.URL)
+
+	_, err = c.Render("missing", nil)
+	require.Error(t, err)
+}
+
+func TestCatalogPersistence(t *testing.T) {
+	c := newTestCatalog(t)
+	dir := t.TempDir()
+
+	for _, name := range []string{"catalog.json", "catalog.yaml"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, c.Save(path))
+
+		loaded, err := LoadCatalog(path)
+		require.NoError(t, err, name)
+		require.Equal(t, c.Entries(), loaded.Entries(), name)
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, c.WriteYAML(buf))
+	require.Contains(t, buf.String(), "      skip_tls: false\n")
+}
+
+func TestReadCatalogHandWritten(t *testing.T) {
+	c, err := ReadCatalog(bytes.NewBufferString(`
+entries:
+  - name: health
+    tags: [ops]
+    request:
+      method: GET
+      url: https://api.site.com/health
+`))
+	require.NoError(t, err)
+	e, ok := c.Get("health")
+	require.True(t, ok)
+	require.Equal(t, &Request{Method: "GET", URL: "https://api.site.com/health", Header: Header{}}, e.Request)
+}
@@ -0,0 +1,80 @@
+package gcurl
+
+import "strings"
+
+// ChainResult is the outcome of parsing one command of a chain.
+type ChainResult struct {
+	// Index is the position of the command among the curl commands of
+	// the chain.
+	Index   int
+	Command string
+	Request *Request
+	Err     error
+}
+
+// ParseChain parses the curl commands of a shell command list such as
+// "curl A && curl B" or "curl A; curl B", using the default Parser.
+func ParseChain(cmds string) []ChainResult {
+	return defaultParser.ParseChain(cmds)
+}
+
+// ParseChain splits cmds on the shell list operators &&, ||, ; and & and
+// on unescaped newlines, then parses every curl command found. Other
+// commands, e.g. "cd dir" or "export TOKEN=...", are skipped. Errors are
+// reported per command.
+func (p *Parser) ParseChain(cmds string) []ChainResult {
+	var res []ChainResult
+	for _, cmd := range splitCommands(cmds) {
+		if !strings.HasPrefix(cmd, "curl ") {
+			continue
+		}
+		r := ChainResult{Index: len(res), Command: cmd}
+		r.Request, r.Err = p.Parse(cmd)
+		res = append(res, r)
+	}
+	return res
+}
+
+// splitCommands splits a shell command list on its list operators,
+// honoring quotes, escapes and line continuations.
+func splitCommands(s string) []string {
+	var cmds []string
+	start := 0
+	add := func(end int) {
+		cmd := strings.TrimSpace(s[start:end])
+		// Drop the continuation of a line ending with an operator.
+		for strings.HasPrefix(cmd, "\\\n") || strings.HasPrefix(cmd, "\\\r\n") {
+			cmd = strings.TrimSpace(cmd[strings.IndexByte(cmd, '\n')+1:])
+		}
+		if cmd != "" {
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	var single, double bool
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case single:
+			single = c != '\''
+		case c == '\\':
+			i++
+		case c == '"':
+			double = !double
+		case double:
+		case c == '\'':
+			single = true
+		case c == ';' || c == '\n':
+			add(i)
+			start = i + 1
+		case c == '&' || c == '|' && i+1 < len(s) && s[i+1] == '|':
+			add(i)
+			if i+1 < len(s) && s[i+1] == c {
+				i++
+			}
+			start = i + 1
+		}
+	}
+	add(len(s))
+	return cmds
+}
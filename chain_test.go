@@ -0,0 +1,51 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCommands(t *testing.T) {
+	var tests = []struct {
+		given    string
+		expected []string
+	}{
+		{"curl a && curl b", []string{"curl a", "curl b"}},
+		{"curl a; curl b;", []string{"curl a", "curl b"}},
+		{"curl a || curl b & curl c", []string{"curl a", "curl b", "curl c"}},
+		{"curl a | jq . && curl b", []string{"curl a | jq .", "curl b"}},
+		{"curl a\ncurl b", []string{"curl a", "curl b"}},
+		{"curl \\\n  a && curl b", []string{"curl \\\n  a", "curl b"}},
+		{"curl a && \\\n  curl b", []string{"curl a", "curl b"}},
+		{`curl -d 'a;b&&c' "x;y" z\;w`, []string{`curl -d 'a;b&&c' "x;y" z\;w`}},
+		{`curl -d "it's; \"ok\"" a`, []string{`curl -d "it's; \"ok\"" a`}},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, splitCommands(tt.given), tt.given)
+	}
+}
+
+func TestParseChain(t *testing.T) {
+	given := "export TOKEN=abc && curl -X POST -d 'user=sid' https://api.site.com/login && \\\n" +
+		"  curl -H 'Accept: application/json' https://api.site.com/sloths | jq .\n" +
+		"echo done; curl -H 'bad https://api.site.com"
+
+	actual := ParseChain(given)
+	require.Len(t, actual, 3)
+
+	require.Equal(t, 0, actual[0].Index)
+	require.NoError(t, actual[0].Err)
+	require.Equal(t, "POST", actual[0].Request.Method)
+	require.Equal(t, "https://api.site.com/login", actual[0].Request.URL)
+
+	require.Equal(t, 1, actual[1].Index)
+	require.NoError(t, actual[1].Err)
+	require.Equal(t, "https://api.site.com/sloths", actual[1].Request.URL)
+	require.Equal(t, "curl -H 'Accept: application/json' https://api.site.com/sloths | jq .", actual[1].Command)
+
+	require.Equal(t, 2, actual[2].Index)
+	require.Error(t, actual[2].Err)
+	require.Nil(t, actual[2].Request)
+}
@@ -0,0 +1,128 @@
+package gcurl
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedCharset is returned for charsets without a converter.
+var ErrUnsupportedCharset = errors.New("unsupported charset")
+
+// windows1252 maps the 0x80-0x9F range of Windows-1252, where it differs
+// from ISO-8859-1. Unassigned bytes map to the same code point.
+var windows1252 = [32]rune{
+	0x20AC, 0x0081, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0x008D, 0x017D, 0x008F,
+	0x0090, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0x009D, 0x017E, 0x0178,
+}
+
+// charsetTable returns the byte to rune table of a single-byte charset,
+// or nil for UTF-8.
+func charsetTable(charset string) (*[256]rune, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "", "utf-8", "utf8":
+		return nil, nil
+	case "us-ascii", "ascii":
+		return &asciiTable, nil
+	case "iso-8859-1", "iso8859-1", "iso_8859-1", "latin1", "l1":
+		return &latin1Table, nil
+	case "windows-1252", "cp1252":
+		return &windows1252Table, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnsupportedCharset, charset)
+}
+
+var asciiTable, latin1Table, windows1252Table [256]rune
+
+func init() {
+	for i := range latin1Table {
+		latin1Table[i] = rune(i)
+		windows1252Table[i] = rune(i)
+		asciiTable[i] = utf8.RuneError
+		if i < utf8.RuneSelf {
+			asciiTable[i] = rune(i)
+		}
+	}
+	for i, r := range windows1252 {
+		windows1252Table[0x80+i] = r
+	}
+}
+
+// DecodeCharset converts b from charset to UTF-8. An empty charset means
+// UTF-8, which is returned as is.
+func DecodeCharset(charset string, b []byte) (string, error) {
+	table, err := charsetTable(charset)
+	if err != nil || table == nil {
+		return string(b), err
+	}
+	var sb strings.Builder
+	sb.Grow(len(b))
+	for _, c := range b {
+		sb.WriteRune(table[c])
+	}
+	return sb.String(), nil
+}
+
+// EncodeCharset converts the UTF-8 string s to charset. It fails if s has
+// characters the charset cannot represent.
+func EncodeCharset(charset, s string) ([]byte, error) {
+	table, err := charsetTable(charset)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return []byte(s), nil
+	}
+
+	res := make([]byte, 0, len(s))
+	for _, r := range s {
+		c, ok := encodeRune(table, r)
+		if !ok {
+			return nil, fmt.Errorf("character %q not representable in %s", r, charset)
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+func encodeRune(table *[256]rune, r rune) (byte, bool) {
+	if r < utf8.RuneSelf {
+		return byte(r), true
+	}
+	for i := utf8.RuneSelf; i < len(table); i++ {
+		if table[i] == r && r != utf8.RuneError {
+			return byte(i), true
+		}
+	}
+	return 0, false
+}
+
+// Charset returns the lower-cased body charset declared in the
+// Content-Type header, or "" if none is declared.
+func (r *Request) Charset() string {
+	if _, params, err := mime.ParseMediaType(r.Header[KeyContentType]); err == nil {
+		return strings.ToLower(params["charset"])
+	}
+	return ""
+}
+
+// DecodedBody returns the body converted from its declared charset to
+// UTF-8, for display and editing. Body itself keeps the original bytes.
+func (r *Request) DecodedBody() (string, error) {
+	return DecodeCharset(r.Charset(), []byte(r.Body))
+}
+
+// SetDecodedBody sets the body from UTF-8 text, converting it to the
+// declared charset so the request is sent with the expected bytes.
+func (r *Request) SetDecodedBody(text string) error {
+	b, err := EncodeCharset(r.Charset(), text)
+	if err != nil {
+		return err
+	}
+	r.Body = string(b)
+	return nil
+}
@@ -0,0 +1,58 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCharsetConversion(t *testing.T) {
+	var tests = []struct {
+		charset string
+		raw     string
+		text    string
+	}{
+		{"", "caf\xc3\xa9", "café"},
+		{"UTF-8", "caf\xc3\xa9", "café"},
+		{"ISO-8859-1", "caf\xe9 \xa3", "café £"},
+		{"latin1", "na\xefve", "naïve"},
+		{"windows-1252", "\x93quoted\x94 \x80", "“quoted” €"},
+		{"us-ascii", "plain", "plain"},
+	}
+
+	for _, tt := range tests {
+		text, err := DecodeCharset(tt.charset, []byte(tt.raw))
+		require.NoError(t, err, tt.charset)
+		require.Equal(t, tt.text, text, tt.charset)
+
+		raw, err := EncodeCharset(tt.charset, tt.text)
+		require.NoError(t, err, tt.charset)
+		require.Equal(t, tt.raw, string(raw), tt.charset)
+	}
+
+	_, err := EncodeCharset("iso-8859-1", "“€”")
+	require.Error(t, err)
+	_, err = EncodeCharset("us-ascii", "café")
+	require.Error(t, err)
+	_, err = DecodeCharset("shift_jis", []byte("x"))
+	require.ErrorIs(t, err, ErrUnsupportedCharset)
+}
+
+func TestRequestDecodedBody(t *testing.T) {
+	req := &Request{
+		Method: "POST",
+		URL:    "https://api.site.com",
+		Header: Header{KeyContentType: "text/plain; charset=ISO-8859-1"},
+		Body:   "caf\xe9",
+	}
+	require.Equal(t, "iso-8859-1", req.Charset())
+	text, err := req.DecodedBody()
+	require.NoError(t, err)
+	require.Equal(t, "café", text)
+
+	require.NoError(t, req.SetDecodedBody(text+" crème"))
+	require.Equal(t, "caf\xe9 cr\xe8me", req.Body)
+
+	require.Error(t, req.SetDecodedBody("€"))
+	require.Equal(t, "caf\xe9 cr\xe8me", req.Body)
+}
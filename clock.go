@@ -0,0 +1,84 @@
+package gcurl
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Clock tells the time to the executor: cache freshness, cookie file
+// expiry, request timeouts, DNSCache entries and ClientCredentials tokens
+// are measured with it. Tests inject a fake clock with WithClock to move
+// time forward without sleeping.
+type Clock interface {
+	Now() time.Time
+	// AfterFunc calls f in its own goroutine once d has elapsed, unless
+	// stop is called first, like time.AfterFunc.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// SystemClock is the real time.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// WithClock measures time with c instead of the system clock. Request
+// timeouts are then enforced by canceling the request when c says so,
+// rather than by http.Client.Timeout.
+func WithClock(c Clock) ExecOption {
+	return func(cfg *execConfig) { cfg.clock = c }
+}
+
+func (c *execConfig) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// afterFunc is AfterFunc on the configured clock.
+func (c *execConfig) afterFunc(d time.Duration, f func()) func() bool {
+	if c.clock == nil {
+		return SystemClock.AfterFunc(d, f)
+	}
+	return c.clock.AfterFunc(d, f)
+}
+
+// withTimeout bounds ctx by timeout on the configured clock. Without a
+// clock, the client enforces the timeout and ctx is returned as is.
+func (c *execConfig) withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if c.clock == nil || timeout <= 0 {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := c.afterFunc(timeout, func() {
+		cancel(fmt.Errorf("timeout after %s: %w", timeout, context.DeadlineExceeded))
+	})
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+type clockKey struct{}
+
+// contextWithClock passes c to the code run on behalf of a request, such
+// as a DNSCache or an AuthProvider.
+func contextWithClock(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// clockNow returns the time on the clock ctx carries, the system time if
+// none.
+func clockNow(ctx context.Context) time.Time {
+	if c, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return c.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,69 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// stubClock is stopped at now and fires timers at once if fire is set.
+type stubClock struct {
+	now  time.Time
+	fire bool
+}
+
+func (c stubClock) Now() time.Time { return c.now }
+
+func (c stubClock) AfterFunc(d time.Duration, f func()) func() bool {
+	if c.fire {
+		go f()
+	}
+	return func() bool { return false }
+}
+
+func TestClockCookieExpiry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Cookie")))
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "cookies.txt")
+	require.NoError(t, os.WriteFile(file, []byte("127.0.0.1\tFALSE\t/\tFALSE\t2000000000\ttoken\txyz\n"), 0o644))
+	req, err := Parse("curl -b " + file + " " + srv.URL)
+	require.NoError(t, err)
+
+	resp, err := req.Execute(context.Background(), WithClock(stubClock{now: time.Unix(1999999999, 0)}))
+	require.NoError(t, err)
+	require.Equal(t, "token=xyz", resp.Text())
+
+	resp, err = req.Execute(context.Background(), WithClock(stubClock{now: time.Unix(2000000001, 0)}))
+	require.NoError(t, err)
+	require.Empty(t, resp.Text())
+}
+
+func TestClockTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+		}
+	}))
+	defer srv.Close()
+
+	req, err := Parse("curl -m 30 " + srv.URL + "/slow")
+	require.NoError(t, err)
+	_, err = req.Execute(context.Background(), WithClock(stubClock{fire: true}))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.EqualError(t, err, "timeout after 30s: context deadline exceeded")
+
+	// Without a timeout, no timer is set.
+	req, err = Parse("curl " + srv.URL)
+	require.NoError(t, err)
+	_, err = req.Execute(context.Background(), WithClock(stubClock{fire: true}))
+	require.NoError(t, err)
+}
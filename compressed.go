@@ -0,0 +1,67 @@
+package gcurl
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// AcceptEncodingCompressed is the Accept-Encoding header --compressed
+// adds when the command doesn't set one. Like curl, it only lists the
+// codings that are decoded.
+const AcceptEncodingCompressed = "gzip, deflate"
+
+// decompressingSend decodes the responses of next, as curl --compressed
+// does.
+func decompressingSend(next func(*http.Client, *http.Request) (*Response, error)) func(*http.Client, *http.Request) (*Response, error) {
+	return func(client *http.Client, hreq *http.Request) (*Response, error) {
+		resp, err := next(client, hreq)
+		if err != nil {
+			return nil, err
+		}
+		if err := resp.decompress(); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// decompress decodes a gzip or deflate body in place. Other codings, such
+// as br when the command asks for it, are left as received along with
+// their Content-Encoding.
+func (r *Response) decompress() error {
+	var zr io.Reader
+	var err error
+	coding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+	switch coding {
+	case "gzip", "x-gzip":
+		zr, err = gzip.NewReader(bytes.NewReader(r.body))
+	case "deflate":
+		// Servers send either zlib, as specified, or raw deflate data.
+		if zr, err = zlib.NewReader(bytes.NewReader(r.body)); err != nil {
+			zr, err = flate.NewReader(bytes.NewReader(r.body)), nil
+		}
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("decompress %s body: %w", coding, err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		return fmt.Errorf("decompress %s body: %w", coding, err)
+	}
+
+	r.body = body
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+	r.Uncompressed = true
+	return nil
+}
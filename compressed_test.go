@@ -0,0 +1,101 @@
+package gcurl
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompressed(t *testing.T) {
+	var tests = []struct {
+		given    string
+		encoding string
+		curl     string
+	}{
+		{`curl --compressed https://api.site.com`, AcceptEncodingCompressed, `curl --compressed https://api.site.com`},
+		{`curl --compressed -H 'Accept-Encoding: gzip' https://api.site.com`, "gzip", `curl -H 'accept-encoding: gzip' --compressed https://api.site.com`},
+	}
+
+	for _, tt := range tests {
+		req, err := Parse(tt.given)
+		require.NoError(t, err, tt.given)
+		require.True(t, req.Compressed, tt.given)
+		require.Equal(t, tt.encoding, req.Header.Get("Accept-Encoding"), tt.given)
+		require.Equal(t, tt.curl, req.curl(), tt.given)
+	}
+}
+
+func TestDoCompressed(t *testing.T) {
+	const text = "sloths sleep up to 15 hours a day"
+	encode := func(coding string) []byte {
+		buf := &bytes.Buffer{}
+		var w io.WriteCloser
+		switch coding {
+		case "gzip":
+			w = gzip.NewWriter(buf)
+		case "deflate":
+			w = zlib.NewWriter(buf)
+		case "raw-deflate":
+			w, _ = flate.NewWriter(buf, flate.DefaultCompression)
+		default:
+			return []byte(text)
+		}
+		_, _ = w.Write([]byte(text))
+		_ = w.Close()
+		return buf.Bytes()
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		coding := r.URL.Query().Get("coding")
+		body := encode(coding)
+		if coding == "raw-deflate" {
+			coding = "deflate"
+		}
+		w.Header().Set("Content-Encoding", coding)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	for _, coding := range []string{"gzip", "deflate", "raw-deflate"} {
+		resp, err := Do(context.Background(), "curl --compressed '"+srv.URL+"?coding="+coding+"'")
+		require.NoError(t, err, coding)
+		require.Equal(t, text, resp.Text(), coding)
+		require.Empty(t, resp.Header.Get("Content-Encoding"), coding)
+		require.True(t, resp.Uncompressed, coding)
+	}
+
+	// Without --compressed, or for codings other than gzip and deflate,
+	// the body is left as received.
+	resp, err := Do(context.Background(), "curl -H 'Accept-Encoding: gzip' '"+srv.URL+"?coding=gzip'")
+	require.NoError(t, err)
+	require.Equal(t, encode("gzip"), resp.Bytes())
+	resp, err = Do(context.Background(), "curl --compressed '"+srv.URL+"?coding=br'")
+	require.NoError(t, err)
+	require.Equal(t, "br", resp.Header.Get("Content-Encoding"))
+
+	// A server preferring br falls back to an advertised coding.
+	negotiating := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "br") {
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = w.Write([]byte{0x1b, 0x20, 0x00})
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(encode("gzip"))
+	}))
+	defer negotiating.Close()
+	resp, err = Do(context.Background(), "curl --compressed "+negotiating.URL)
+	require.NoError(t, err)
+	require.Equal(t, text, resp.Text())
+
+	require.ErrorContains(t, (&Response{Response: &http.Response{Header: http.Header{"Content-Encoding": {"gzip"}}}, body: []byte("plain")}).decompress(), "decompress gzip body")
+}
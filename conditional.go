@@ -0,0 +1,80 @@
+package gcurl
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	KeyIfNoneMatch     = "if-none-match"
+	KeyIfModifiedSince = "if-modified-since"
+)
+
+// Validators are the cache validators of a response, used to make the
+// next request for the same resource conditional.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// Validators returns the ETag and Last-Modified headers of the response.
+func (r *Response) Validators() Validators {
+	if r.Response == nil {
+		return Validators{}
+	}
+	return Validators{
+		ETag:         strings.TrimSpace(r.Header.Get("ETag")),
+		LastModified: strings.TrimSpace(r.Header.Get("Last-Modified")),
+	}
+}
+
+// NotModified reports whether the response is a 304 Not Modified answer
+// to a conditional request.
+func (r *Response) NotModified() bool {
+	return r.Response != nil && r.StatusCode == http.StatusNotModified
+}
+
+// Conditional returns the validators sent in If-None-Match and
+// If-Modified-Since.
+func (r *Request) Conditional() Validators {
+	return Validators{
+		ETag:         r.Header[KeyIfNoneMatch],
+		LastModified: r.Header[KeyIfModifiedSince],
+	}
+}
+
+// SetConditional sends v in If-None-Match and If-Modified-Since, removing
+// the headers whose validator is empty.
+func (r *Request) SetConditional(v Validators) {
+	setOrDelete := func(key, value string) {
+		if value == "" {
+			delete(r.Header, key)
+			return
+		}
+		r.Header[key] = value
+	}
+	setOrDelete(KeyIfNoneMatch, v.ETag)
+	setOrDelete(KeyIfModifiedSince, v.LastModified)
+}
+
+// Revalidate makes the request conditional on resp, a previous response
+// to it, so that polling the same resource only transfers it again once
+// it changed. A 304 response keeps the current validators, updated with
+// any it sends.
+func (r *Request) Revalidate(resp *Response) {
+	v := resp.Validators()
+	if resp.NotModified() {
+		cur := r.Conditional()
+		if v.ETag == "" {
+			v.ETag = cur.ETag
+		}
+		if v.LastModified == "" {
+			v.LastModified = cur.LastModified
+		}
+	}
+	r.SetConditional(v)
+}
@@ -0,0 +1,79 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditional(t *testing.T) {
+	req, err := Parse(`curl -H 'If-None-Match: "v1"' https://api.site.com/sloths`)
+	require.NoError(t, err)
+	require.Equal(t, Validators{ETag: `"v1"`}, req.Conditional())
+
+	req.SetConditional(Validators{LastModified: "Wed, 21 Oct 2015 07:28:00 GMT"})
+	require.Equal(t, Header{KeyIfModifiedSince: "Wed, 21 Oct 2015 07:28:00 GMT"}, req.Header)
+
+	req.SetConditional(Validators{})
+	require.Empty(t, req.Header)
+	require.True(t, req.Conditional().IsZero())
+}
+
+func TestRevalidate(t *testing.T) {
+	var tests = []struct {
+		name     string
+		current  Validators
+		status   int
+		etag     string
+		expected Validators
+	}{
+		{"fresh response", Validators{}, http.StatusOK, `"v1"`, Validators{ETag: `"v1"`, LastModified: "Mon"}},
+		{"changed response drops stale validators", Validators{ETag: `"v0"`}, http.StatusOK, "", Validators{LastModified: "Mon"}},
+		{"not modified keeps validators", Validators{ETag: `"v1"`, LastModified: "Sun"}, http.StatusNotModified, "", Validators{ETag: `"v1"`, LastModified: "Mon"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := &Request{Method: http.MethodGet, URL: "https://api.site.com", Header: Header{}}
+			req.SetConditional(tt.current)
+
+			resp := newTestResponse("", "")
+			resp.StatusCode = tt.status
+			resp.Header.Set("Last-Modified", "Mon")
+			if tt.etag != "" {
+				resp.Header.Set("ETag", tt.etag)
+			}
+			req.Revalidate(resp)
+			require.Equal(t, tt.expected, req.Conditional())
+		})
+	}
+}
+
+func TestRevalidatePolling(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("sloths"))
+	}))
+	defer srv.Close()
+
+	req, err := Parse("curl " + srv.URL)
+	require.NoError(t, err)
+
+	resp, err := req.do(context.Background(), newExecConfig(nil))
+	require.NoError(t, err)
+	require.False(t, resp.NotModified())
+	require.Equal(t, "sloths", resp.Text())
+
+	req.Revalidate(resp)
+	resp, err = req.do(context.Background(), newExecConfig(nil))
+	require.NoError(t, err)
+	require.True(t, resp.NotModified())
+}
@@ -0,0 +1,78 @@
+package gcurl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrContentLength is returned when a Content-Length header conflicts
+// with the body or with chunked Transfer-Encoding.
+var ErrContentLength = errors.New("conflicting Content-Length")
+
+// WithContentLengthOverride lets the body size replace a Content-Length
+// header that conflicts with it, as happens with hand-edited commands,
+// instead of failing with ErrContentLength.
+func WithContentLengthOverride() ParseOption {
+	return func(p *Parser) { p.contentLengthOverride = true }
+}
+
+// isChunked reports whether the headers ask for chunked transfer coding.
+func isChunked(h Header) bool {
+	return strings.Contains(strings.ToLower(h.Get("transfer-encoding")), "chunked")
+}
+
+// checkContentLength checks a Content-Length header against size, the
+// body size or -1 if unknown yet, and against chunked transfer coding.
+func checkContentLength(h Header, size int64) error {
+	v, ok := h.lookup("content-length")
+	if !ok {
+		return nil
+	}
+	if isChunked(h) {
+		return fmt.Errorf("%w: %s with chunked Transfer-Encoding", ErrContentLength, v)
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("%w: invalid value %q", ErrContentLength, v)
+	}
+	if size >= 0 && n != size {
+		return fmt.Errorf("%w: %d for a body of %d bytes", ErrContentLength, n, size)
+	}
+	return nil
+}
+
+// contentLength applies the parser policy to the Content-Length of the
+// command. Only inline bodies are checked here; file and form bodies are
+// checked when the request is built.
+func (p *Parser) contentLength(req *Request) error {
+	size := int64(-1)
+	if req.BodyFile == "" && req.UploadFile == "" && len(req.Form) == 0 {
+		size = int64(len(req.Body))
+	}
+	err := checkContentLength(req.Header, size)
+	if err != nil && p.contentLengthOverride {
+		req.Header.Del("content-length")
+		return nil
+	}
+	return err
+}
+
+// setTransferLength makes hreq send its body chunked when the request
+// asks for it, and otherwise checks a Content-Length header against the
+// body size computed by net/http.
+func (r *Request) setTransferLength(hreq *http.Request) error {
+	if isChunked(r.Header) {
+		if r.Header.Has("content-length") {
+			return checkContentLength(r.Header, -1)
+		}
+		if hreq.Body != nil && hreq.Body != http.NoBody {
+			hreq.TransferEncoding = []string{"chunked"}
+			hreq.ContentLength = -1
+		}
+		return nil
+	}
+	return checkContentLength(r.Header, hreq.ContentLength)
+}
@@ -0,0 +1,97 @@
+package gcurl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContentLength(t *testing.T) {
+	var tests = []struct {
+		name  string
+		given string
+		err   string
+	}{
+		{"matching", `curl -H 'Content-Length: 7' -d 'name=id' https://api.site.com`, ""},
+		{"no body", `curl -H 'Content-Length: 0' https://api.site.com`, ""},
+		{"json as written", `curl -H 'Content-Type: application/json' -H 'Content-Length: 18' -d '{"hello": "world"}' https://api.site.com`, ""},
+		{"file checked when sent", `curl -H 'Content-Length: 5' --data-binary @sid.bin https://api.site.com`, ""},
+		{"mismatch", `curl -H 'Content-Length: 99' -d 'name=id' https://api.site.com`, "conflicting Content-Length: 99 for a body of 7 bytes"},
+		{"invalid", `curl -H 'Content-Length: many' -d 'name=id' https://api.site.com`, `conflicting Content-Length: invalid value "many"`},
+		{"chunked", `curl -H 'Transfer-Encoding: chunked' -H 'Content-Length: 7' -d 'name=id' https://api.site.com`, "conflicting Content-Length: 7 with chunked Transfer-Encoding"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.given)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrContentLength)
+			require.ErrorContains(t, err, tt.err)
+
+			req, err := NewParser(WithContentLengthOverride()).Parse(tt.given)
+			require.NoError(t, err)
+			require.NotContains(t, req.Header, "content-length")
+			_, err = req.ToHTTPRequest(context.Background())
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestContentLengthJSON(t *testing.T) {
+	req, err := Parse(`curl -H 'Content-Type: application/json' -H 'Content-Length: 18' -d '{"hello": "world"}' https://api.site.com`)
+	require.NoError(t, err)
+	require.Equal(t, `{"hello": "world"}`, req.Body)
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(18), hreq.ContentLength)
+}
+
+func TestTransferLength(t *testing.T) {
+	req, err := Parse(`curl -d 'name=id' https://api.site.com`)
+	require.NoError(t, err)
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(7), hreq.ContentLength)
+	require.Empty(t, hreq.TransferEncoding)
+
+	req, err = Parse(`curl -H 'Transfer-Encoding: chunked' -d 'name=id' https://api.site.com`)
+	require.NoError(t, err)
+	hreq, err = req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), hreq.ContentLength)
+	require.Equal(t, []string{"chunked"}, hreq.TransferEncoding)
+	wire := &bytes.Buffer{}
+	require.NoError(t, hreq.Write(wire))
+	require.Contains(t, wire.String(), "Transfer-Encoding: chunked\r\n")
+	require.NotContains(t, wire.String(), "Content-Length")
+
+	file := filepath.Join(t.TempDir(), "sid.bin")
+	require.NoError(t, os.WriteFile(file, []byte("sloth"), 0o644))
+	req, err = Parse(`curl -H 'Content-Length: 4' --data-binary @` + file + ` https://api.site.com`)
+	require.NoError(t, err)
+	_, err = req.ToHTTPRequest(context.Background())
+	require.ErrorIs(t, err, ErrContentLength)
+
+	req.Header["content-length"] = "5"
+	hreq, err = req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	body, err := io.ReadAll(hreq.Body)
+	require.NoError(t, err)
+	require.Equal(t, "sloth", string(body))
+	require.Empty(t, hreq.Header.Get("Content-Length"))
+
+	// Requests without a body keep net/http defaults.
+	hreq, err = (&Request{Method: http.MethodGet, URL: "https://api.site.com", Header: Header{"transfer-encoding": "chunked"}}).ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, hreq.TransferEncoding)
+}
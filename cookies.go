@@ -0,0 +1,141 @@
+package gcurl
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cookies parses the Cookie header, as given with -b 'a=1; b=2'. Cookies
+// of CookieFile are not included, see FileCookies.
+func (r *Request) Cookies() []*http.Cookie {
+	v, ok := r.Header[KeyCookie]
+	if !ok {
+		return nil
+	}
+	return (&http.Request{Header: http.Header{"Cookie": {v}}}).Cookies()
+}
+
+// WithCookieFiles makes the parser read -b cookie files, through the
+// FileReader if one is set, and add the cookies they hold for the URL to
+// the Cookie header instead of recording Request.CookieFile.
+func WithCookieFiles() ParseOption {
+	return func(p *Parser) { p.cookieFiles = true }
+}
+
+// loadCookieFile moves the cookies of the request's cookie file to its
+// Cookie header.
+func (p *Parser) loadCookieFile(req *Request) error {
+	read := p.fileReader
+	if read == nil {
+		read = FileReaderFunc(os.ReadFile)
+	}
+	data, err := read.ReadFile(req.CookieFile)
+	if err != nil {
+		return fmt.Errorf("read cookie file: %w", err)
+	}
+	cookies, err := parseCookieFile(req.CookieFile, bytes.NewReader(data), req.URL, time.Now())
+	if err != nil {
+		return err
+	}
+	pairs := make([]string, 0, len(cookies)+1)
+	if v, ok := req.Header[KeyCookie]; ok {
+		pairs = append(pairs, v)
+	}
+	for _, c := range cookies {
+		pairs = append(pairs, c.Name+"="+c.Value)
+	}
+	if len(pairs) > 0 {
+		req.Header[KeyCookie] = strings.Join(pairs, "; ")
+	}
+	req.CookieFile = ""
+	return nil
+}
+
+// FileCookies reads the Netscape cookie file of -b, as written by curl -c
+// and browser extensions, and returns the unexpired cookies curl would
+// send to the request URL.
+func (r *Request) FileCookies() ([]*http.Cookie, error) {
+	return r.fileCookies(time.Now())
+}
+
+func (r *Request) fileCookies(now time.Time) ([]*http.Cookie, error) {
+	if r.CookieFile == "" {
+		return nil, nil
+	}
+	f, err := os.Open(r.CookieFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseCookieFile(r.CookieFile, f, r.URL, now)
+}
+
+func parseCookieFile(name string, file io.Reader, rawURL string, now time.Time) ([]*http.Cookie, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var cookies []*http.Cookie
+	sc := bufio.NewScanner(file)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), "\r")
+		httpOnly := strings.HasPrefix(line, "#HttpOnly_")
+		if httpOnly {
+			line = line[len("#HttpOnly_"):]
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("cookie file %s:%d: expected 7 fields, got %d", name, n, len(fields))
+		}
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cookie file %s:%d: invalid expiry %q", name, n, fields[4])
+		}
+		c := &http.Cookie{
+			Domain:   strings.TrimPrefix(fields[0], "."),
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		if expires != 0 {
+			c.Expires = time.Unix(expires, 0)
+			if c.Expires.Before(now) {
+				continue
+			}
+		}
+		if cookieMatches(c, strings.EqualFold(fields[1], "TRUE"), u) {
+			cookies = append(cookies, c)
+		}
+	}
+	return cookies, sc.Err()
+}
+
+func cookieMatches(c *http.Cookie, subdomains bool, u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	domain := strings.ToLower(c.Domain)
+	if host != domain && !(subdomains && strings.HasSuffix(host, "."+domain)) {
+		return false
+	}
+	if c.Secure && u.Scheme != "https" {
+		return false
+	}
+	p := u.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	return c.Path == "" || p == c.Path || strings.HasPrefix(p, strings.TrimSuffix(c.Path, "/")+"/")
+}
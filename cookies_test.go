@@ -0,0 +1,102 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookies(t *testing.T) {
+	req, err := Parse(`curl -b 'a=1; b=two words' https://api.site.com`)
+	require.NoError(t, err)
+	require.Empty(t, req.CookieFile)
+
+	cookies := req.Cookies()
+	require.Len(t, cookies, 2)
+	require.Equal(t, "a", cookies[0].Name)
+	require.Equal(t, "1", cookies[0].Value)
+	require.Equal(t, "b", cookies[1].Name)
+
+	req, err = Parse(`curl https://api.site.com`)
+	require.NoError(t, err)
+	require.Nil(t, req.Cookies())
+}
+
+func TestCookieFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cookies.txt")
+	require.NoError(t, os.WriteFile(file, []byte("# Netscape HTTP Cookie File\n"+
+		"\n"+
+		".site.com\tTRUE\t/\tFALSE\t0\tsession\tabc\n"+
+		"#HttpOnly_api.site.com\tFALSE\t/sloths\tTRUE\t4102444800\ttoken\txyz\r\n"+
+		"api.site.com\tFALSE\t/\tFALSE\t1\texpired\told\n"+
+		"other.com\tFALSE\t/\tFALSE\t0\tforeign\tno\n"+
+		"api.site.com\tFALSE\t/koalas\tFALSE\t0\tkoala\tno\n"), 0o644))
+
+	req, err := Parse(`curl -b ` + file + ` -b 'theme=dark' https://api.site.com/sloths/4`)
+	require.NoError(t, err)
+	require.Equal(t, file, req.CookieFile)
+	require.Equal(t, "theme=dark", req.Header[KeyCookie])
+
+	cookies, err := req.FileCookies()
+	require.NoError(t, err)
+	require.Len(t, cookies, 2)
+	require.Equal(t, "session", cookies[0].Name)
+	require.Equal(t, "token", cookies[1].Name)
+	require.True(t, cookies[1].HttpOnly)
+	require.True(t, cookies[1].Secure)
+
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "theme=dark; session=abc; token=xyz", hreq.Header.Get("Cookie"))
+
+	// Secure cookies are not sent over plain HTTP.
+	req.URL = "http://api.site.com/sloths"
+	cookies, err = req.FileCookies()
+	require.NoError(t, err)
+	require.Len(t, cookies, 1)
+
+	again, err := Parse(req.String())
+	require.NoError(t, err)
+	require.Equal(t, req, again)
+
+	require.NoError(t, os.WriteFile(file, []byte("api.site.com\tFALSE\t/\n"), 0o644))
+	_, err = req.FileCookies()
+	require.EqualError(t, err, "cookie file "+file+":1: expected 7 fields, got 3")
+
+	req.CookieFile = filepath.Join(t.TempDir(), "missing.txt")
+	_, err = req.ToHTTPRequest(context.Background())
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.Nil(t, (&Request{URL: "https://api.site.com", Header: Header{}}).Cookies())
+	var none []*http.Cookie
+	cookies, err = (&Request{URL: "https://api.site.com"}).FileCookies()
+	require.NoError(t, err)
+	require.Equal(t, none, cookies)
+}
+
+func TestCookieJarAndFileLoading(t *testing.T) {
+	jar := FileReaderFunc(func(name string) ([]byte, error) {
+		require.Equal(t, "cookies.txt", name)
+		return []byte(".site.com\tTRUE\t/\tFALSE\t0\tsession\tabc\n" +
+			"other.com\tFALSE\t/\tFALSE\t0\tforeign\tno\n"), nil
+	})
+
+	req, err := Parse(`curl -b cookies.txt -c jar.txt https://api.site.com`)
+	require.NoError(t, err)
+	require.Equal(t, "cookies.txt", req.CookieFile)
+	require.Equal(t, "jar.txt", req.CookieJarFile)
+	require.Equal(t, "curl -b cookies.txt -c jar.txt https://api.site.com", req.String())
+
+	req, err = NewParser(WithCookieFiles(), WithFileReader(jar)).Parse(`curl --cookie-jar jar.txt -b cookies.txt -b 'theme=dark' https://api.site.com`)
+	require.NoError(t, err)
+	require.Empty(t, req.CookieFile)
+	require.Equal(t, "jar.txt", req.CookieJarFile)
+	require.Equal(t, "theme=dark; session=abc", req.Header[KeyCookie])
+
+	_, err = NewParser(WithCookieFiles()).Parse(`curl -b ` + filepath.Join(t.TempDir(), "missing.txt") + ` https://api.site.com`)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
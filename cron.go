@@ -0,0 +1,283 @@
+package gcurl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule computes the activation times of a recurring job.
+type Schedule interface {
+	// Next returns the first activation time strictly after t.
+	Next(t time.Time) time.Time
+}
+
+// ParseSchedule parses a standard five-field cron expression (minute,
+// hour, day of month, month, day of week) supporting *, lists, ranges and
+// steps, one of the @hourly, @daily, @weekly, @monthly and @yearly
+// macros, or "@every <duration>" such as "@every 30s".
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := strings.CutPrefix(spec, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", spec, err)
+		}
+		if every <= 0 {
+			return nil, fmt.Errorf("schedule %q: non-positive interval", spec)
+		}
+		return everySchedule(every), nil
+	}
+	if macro, ok := cronMacros[spec]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+	s := &cronSchedule{}
+	bits := []*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, f := range fields {
+		b, err := parseCronField(f, cronBounds[i])
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", spec, err)
+		}
+		*bits[i] = b
+	}
+	// Sunday can be written as 0 or 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.anyDom = fields[2] == "*"
+	s.anyDow = fields[4] == "*"
+	// Days such as February 30 never come: Next would find no activation.
+	if s.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("schedule %q: never matches", spec)
+	}
+	return s, nil
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronBounds = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+func parseCronField(field string, bounds [2]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		expr, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := bounds[0], bounds[1]
+		if expr != "*" {
+			from, to, isRange := strings.Cut(expr, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid value in %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("invalid value in %q", part)
+				}
+			} else if hasStep {
+				hi = bounds[1]
+			}
+		}
+		if lo < bounds[0] || hi > bounds[1] || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, bounds[0], bounds[1])
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	anyDom, anyDow                bool
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Every valid expression matches within a leap cycle.
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches follows cron: when both day fields are restricted, a day
+// matching either of them is selected.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDom || s.anyDow {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+type everySchedule time.Duration
+
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// CronResult is the outcome of one scheduled execution.
+type CronResult struct {
+	Name     string
+	Time     time.Time
+	Duration time.Duration
+	Request  *Request
+	Response *Response
+	Err      error
+}
+
+// CronRunner executes the catalog entries that have a Schedule, turning
+// a catalog into simple uptime or synthetic checks.
+type CronRunner struct {
+	Catalog *Catalog
+	// Environment, if set, names the catalog environment merged into the
+	// requests, with Vars taking precedence over its variables.
+	Environment string
+	Vars        map[string]string
+	// Options apply to every execution.
+	Options []ExecOption
+	// OnResult is called after every execution, possibly concurrently.
+	OnResult func(CronResult)
+}
+
+type cronJob struct {
+	entry    *CatalogEntry
+	schedule Schedule
+	next     time.Time
+}
+
+// Run executes the scheduled entries until ctx is done, then waits for
+// executions in flight and returns ctx.Err(). Entries are read from the
+// catalog when Run starts. An execution still running when its entry is
+// due again is not overlapped: that activation is skipped. Activations
+// are timed with the clock of the options, see WithClock.
+func (r *CronRunner) Run(ctx context.Context) error {
+	cfg := newExecConfig(r.Options)
+	now := cfg.now()
+	var jobs []*cronJob
+	for _, e := range r.Catalog.Entries() {
+		if e.Schedule == "" {
+			continue
+		}
+		s, err := ParseSchedule(e.Schedule)
+		if err != nil {
+			return fmt.Errorf("cron: entry %q: %w", e.Name, err)
+		}
+		jobs = append(jobs, &cronJob{entry: e, schedule: s, next: s.Next(now)})
+	}
+	if len(jobs) == 0 {
+		return fmt.Errorf("cron: no scheduled entries")
+	}
+
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	running := map[string]bool{}
+	mu := &sync.Mutex{}
+
+	due := make(chan struct{}, 1)
+	for {
+		// Jobs without a next activation are done.
+		var next time.Time
+		for _, j := range jobs {
+			if !j.next.IsZero() && (next.IsZero() || j.next.Before(next)) {
+				next = j.next
+			}
+		}
+		if next.IsZero() {
+			return fmt.Errorf("cron: no future activations")
+		}
+
+		stop := cfg.afterFunc(next.Sub(cfg.now()), func() { due <- struct{}{} })
+		select {
+		case <-ctx.Done():
+			stop()
+			return ctx.Err()
+		case <-due:
+		}
+		now = cfg.now()
+
+		for _, j := range jobs {
+			if j.next.IsZero() || j.next.After(now) {
+				continue
+			}
+			j.next = j.schedule.Next(now)
+
+			name := j.entry.Name
+			mu.Lock()
+			busy := running[name]
+			running[name] = true
+			mu.Unlock()
+			if busy {
+				continue
+			}
+
+			wg.Add(1)
+			go func(e *CatalogEntry, at time.Time) {
+				defer wg.Done()
+				res := r.execute(ctx, cfg, e, at)
+				mu.Lock()
+				delete(running, e.Name)
+				mu.Unlock()
+				if r.OnResult != nil {
+					r.OnResult(res)
+				}
+			}(j.entry, now)
+		}
+	}
+}
+
+func (r *CronRunner) execute(ctx context.Context, cfg *execConfig, e *CatalogEntry, at time.Time) CronResult {
+	res := CronResult{Name: e.Name, Time: at}
+	if r.Environment != "" {
+		res.Request, res.Err = r.Catalog.RenderEnv(e.Name, r.Environment, r.Vars)
+	} else {
+		res.Request, res.Err = r.Catalog.Render(e.Name, r.Vars)
+	}
+	if res.Err != nil {
+		return res
+	}
+
+	start := cfg.now()
+	res.Response, res.Err = res.Request.do(ctx, cfg)
+	res.Duration = cfg.now().Sub(start)
+	return res
+}
@@ -0,0 +1,109 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	base := time.Date(2024, time.February, 28, 10, 17, 30, 0, time.UTC)
+
+	var tests = []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.February, 28, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.February, 28, 10, 30, 0, 0, time.UTC)},
+		{"5,10 9-11 * * *", time.Date(2024, time.February, 28, 11, 5, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"30 8 * * 1-5", time.Date(2024, time.February, 29, 8, 30, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, time.March, 3, 12, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 4", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.February, 28, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		require.NoError(t, err, tt.spec)
+		require.Equal(t, tt.expected, s.Next(base), tt.spec)
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every -1s", "@every soon"} {
+		_, err := ParseSchedule(spec)
+		require.Error(t, err, spec)
+	}
+
+	// Dates that never happen are rejected.
+	for _, spec := range []string{"0 0 30 2 *", "0 0 31 4,6,9,11 *"} {
+		_, err := ParseSchedule(spec)
+		require.ErrorContains(t, err, "never matches", spec)
+	}
+}
+
+func TestCronRunner(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	c := NewCatalog()
+	for _, e := range []*CatalogEntry{
+		{Name: "health", Schedule: "@every 20ms", Request: &Request{Method: "GET", URL: "{{base}}/health", Header: Header{}}},
+		{Name: "down", Schedule: "@every 20ms", Request: &Request{Method: "GET", URL: "{{base}}/down", Header: Header{}}},
+		{Name: "manual", Request: &Request{Method: "GET", URL: "{{base}}/manual", Header: Header{}}},
+	} {
+		require.NoError(t, c.Add(e))
+	}
+
+	var results []CronResult
+	r := &CronRunner{
+		Catalog: c,
+		Vars:    map[string]string{"base": server.URL},
+		OnResult: func(res CronResult) {
+			mu.Lock()
+			results = append(results, res)
+			mu.Unlock()
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, r.Run(ctx), context.DeadlineExceeded)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.GreaterOrEqual(t, hits["/health"], 3)
+	require.GreaterOrEqual(t, hits["/down"], 3)
+	require.Zero(t, hits["/manual"])
+	require.Len(t, results, hits["/health"]+hits["/down"])
+	for _, res := range results {
+		require.NoError(t, res.Err)
+		if res.Name == "down" {
+			require.Equal(t, http.StatusServiceUnavailable, res.Response.StatusCode)
+		}
+	}
+}
+
+func TestCronRunnerErrors(t *testing.T) {
+	c := NewCatalog()
+	r := &CronRunner{Catalog: c}
+	require.Error(t, r.Run(context.Background()))
+
+	require.NoError(t, c.Add(&CatalogEntry{Name: "bad", Schedule: "every minute", Request: &Request{Header: Header{}}}))
+	require.Error(t, r.Run(context.Background()))
+}
@@ -0,0 +1,126 @@
+package gcurl
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// curl renders the request as a curl command line that Parse reads back
+// into an equivalent request.
+func (r *Request) curl() string {
+	args := []string{"curl"}
+	switch {
+	case r.Method == http.MethodHead && !r.hasBody():
+		args = append(args, "-I")
+	case r.UploadFile != "" && r.Method == http.MethodPut:
+	case r.Method == http.MethodGet && !r.hasBody(), r.Method == http.MethodPost && r.hasBody() && r.UploadFile == "":
+	default:
+		args = append(args, "-X", shellQuote(r.Method))
+	}
+	for _, k := range sortedKeys(r.Header) {
+		if r.Compressed && k == "accept-encoding" && r.Header[k] == AcceptEncodingCompressed {
+			// Added back by --compressed.
+			continue
+		}
+		if k == KeyAuthorization && r.derivedHeader() && len(r.HeaderValues(k)) == 1 {
+			// Added back from the credentials.
+			continue
+		}
+		for _, v := range r.HeaderValues(k) {
+			args = append(args, "-H", shellQuote(k+": "+v))
+		}
+	}
+	if r.Body != "" {
+		args = append(args, "--data-raw", shellQuote(r.Body))
+	} else if r.BodyFile != "" {
+		args = append(args, "--data-binary", shellQuote("@"+r.BodyFile))
+	} else if r.UploadFile != "" {
+		args = append(args, "-T", shellQuote(r.UploadFile))
+	} else {
+		for _, f := range r.Form {
+			flag, arg := f.curlArg()
+			args = append(args, flag, shellQuote(arg))
+		}
+	}
+	if r.Auth != nil {
+		args = append(args, r.Auth.curlArgs()...)
+	}
+	if r.CookieFile != "" {
+		args = append(args, "-b", shellQuote(r.CookieFile))
+	}
+	if r.CookieJarFile != "" {
+		args = append(args, "-c", shellQuote(r.CookieJarFile))
+	}
+	if r.SkipTLS {
+		args = append(args, "-k")
+	}
+	if r.Compressed {
+		args = append(args, "--compressed")
+	}
+	if r.Timeout != "" {
+		args = append(args, "-m", shellQuote(r.Timeout))
+	}
+	if r.ConnectTimeout > 0 {
+		args = append(args, "--connect-timeout", FormatDuration(r.ConnectTimeout))
+	}
+	switch {
+	case r.TrustRedirects:
+		args = append(args, "--location-trusted")
+	case r.FollowRedirects:
+		args = append(args, "-L")
+	}
+	if r.AutoReferer {
+		args = append(args, "-e", "';auto'")
+	}
+	if r.MaxRedirects != 0 {
+		args = append(args, "--max-redirs", strconv.Itoa(r.MaxRedirects))
+	}
+	if r.Proxy != "" {
+		args = append(args, "-x", shellQuote(r.Proxy))
+	}
+	if r.ProxyAuth != "" {
+		args = append(args, "-U", shellQuote(r.ProxyAuth))
+	}
+	if len(r.NoProxy) > 0 {
+		args = append(args, "--noproxy", shellQuote(strings.Join(r.NoProxy, ",")))
+	}
+	if !isURL(r.URL) {
+		// Not recognized as the URL when read back without the flag.
+		args = append(args, "--url")
+	}
+	args = append(args, shellQuote(r.URL))
+	return strings.Join(args, " ")
+}
+
+// String renders the request as a properly quoted curl command that
+// Parse reads back into an equivalent request. Secrets are included: use
+// StringRedacted for logs.
+func (r *Request) String() string {
+	return r.curl()
+}
+
+// ToCurl is the same as String.
+func (r *Request) ToCurl() string {
+	return r.curl()
+}
+
+func (r *Request) hasBody() bool {
+	return r.Body != "" || r.BodyFile != "" || r.UploadFile != "" || len(r.Form) > 0
+}
+
+// StringRedacted renders the request as a curl command with the secrets
+// listed by SensitiveHeaders and SensitiveParams, and URL passwords,
+// replaced by REDACTED, so it can be printed or logged safely.
+func (r *Request) StringRedacted() string {
+	return replaceSecrets(r, redact).curl()
+}
+
+// shellQuote quotes s for POSIX shells when it contains anything but
+// plainly safe characters.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_@%+=:,./-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
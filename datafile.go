@@ -0,0 +1,97 @@
+package gcurl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDataFileCombined is returned when a --data-binary file, read at send
+// time, is combined with other data.
+var ErrDataFileCombined = errors.New("data file combined with other data, a FileReader is required")
+
+// ErrNoFileReader is returned for file arguments that must be read while
+// parsing, as with --data-urlencode or -G, when the parser has no
+// FileReader.
+var ErrNoFileReader = errors.New("reading files requires a FileReader")
+
+// FileReader reads the files referenced by "@file" data arguments.
+// fs.ReadFileFS implementations such as os.DirFS satisfy it.
+type FileReader interface {
+	ReadFile(name string) ([]byte, error)
+}
+
+// FileReaderFunc adapts a function to a FileReader.
+type FileReaderFunc func(name string) ([]byte, error)
+
+func (f FileReaderFunc) ReadFile(name string) ([]byte, error) {
+	return f(name)
+}
+
+// OSFileReader reads files from the local file system, and "-" from
+// standard input as curl does.
+var OSFileReader FileReader = FileReaderFunc(readBodyFile)
+
+// WithFileReader makes the parser read "@file" arguments of -d and
+// --data-binary into the body. Without a reader, a --data-binary file is
+// recorded in Request.BodyFile and read when the request is sent, while
+// -d values are kept as is.
+func WithFileReader(r FileReader) ParseOption {
+	return func(p *Parser) { p.fileReader = r }
+}
+
+// dataArg returns the body chunk of a data argument given with flag
+// ("data", "data-raw" or "data-binary"), reading referenced files. file is
+// set instead when the reference is left for later.
+func (p *Parser) dataArg(flag, arg string) (chunk, file string, err error) {
+	name, ok := strings.CutPrefix(arg, "@")
+	if !ok || flag == "data-raw" {
+		return arg, "", nil
+	}
+	if p.fileReader == nil {
+		if flag == "data-binary" {
+			return "", name, nil
+		}
+		return arg, "", nil
+	}
+
+	b, err := p.fileReader.ReadFile(name)
+	if err != nil {
+		return "", "", fmt.Errorf("read %s: %w", arg, err)
+	}
+	if flag == "data-binary" {
+		return string(b), "", nil
+	}
+	// Like curl, -d strips line breaks from files.
+	return strings.NewReplacer("\r", "", "\n", "").Replace(string(b)), "", nil
+}
+
+// urlencodeArg encodes a --data-urlencode argument: "content", "=content",
+// "name=content", "@file" or "name@file".
+func (p *Parser) urlencodeArg(arg string) (string, error) {
+	i := strings.IndexAny(arg, "=@")
+	if i < 0 {
+		return escapeData(arg), nil
+	}
+	name, content := arg[:i], arg[i+1:]
+	if arg[i] == '@' {
+		if p.fileReader == nil {
+			return "", fmt.Errorf("--data-urlencode %s: %w", arg, ErrNoFileReader)
+		}
+		b, err := p.fileReader.ReadFile(content)
+		if err != nil {
+			return "", fmt.Errorf("read %s: %w", content, err)
+		}
+		content = string(b)
+	}
+	if name == "" {
+		return escapeData(content), nil
+	}
+	return name + "=" + escapeData(content), nil
+}
+
+// escapeData percent-encodes everything but unreserved characters, as
+// curl does: unlike url.QueryEscape, spaces become %20.
+func escapeData(s string) string {
+	return escapeBytes(s, func(c byte) bool { return !isUnreserved(c) })
+}
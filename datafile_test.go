@@ -0,0 +1,115 @@
+package gcurl
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDataFile(t *testing.T) {
+	files := fstest.MapFS{
+		"payload.bin": {Data: []byte("\x00\x01\n\x02")},
+		"form.txt":    {Data: []byte("a=1\r\n&b=2\n")},
+	}
+	var tests = []struct {
+		name   string
+		reader FileReader
+		given  string
+		body   string
+		file   string
+		kind   BodyKind
+	}{
+		{"binary recorded", nil, `curl --data-binary @payload.bin https://api.sloths.com`, "", "payload.bin", BodyForm},
+		{"binary recorded typed", nil, `curl -H 'Content-Type: application/json' --data-binary @sid.json https://api.sloths.com`, "", "sid.json", BodyJSON},
+		{"binary recorded untyped", nil, `curl -H 'Content-Type: application/x-sloth' --data-binary @sid.dat https://api.sloths.com`, "", "sid.dat", BodyBinary},
+		{"binary literal", nil, `curl --data-binary 'a=1' https://api.sloths.com`, "a=1", "", BodyForm},
+		{"data kept", nil, `curl -d @form.txt https://api.sloths.com`, "@form.txt", "", BodyText},
+		{"binary read", files, `curl --data-binary @payload.bin https://api.sloths.com`, "\x00\x01\n\x02", "", BodyBinary},
+		{"data read", files, `curl -d @form.txt -d c=3 https://api.sloths.com`, "a=1&b=2&c=3", "", BodyForm},
+		{"raw never read", files, `curl --data-raw @form.txt https://api.sloths.com`, "@form.txt", "", BodyText},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []ParseOption
+			if tt.reader != nil {
+				opts = append(opts, WithFileReader(tt.reader))
+			}
+			actual, err := NewParser(opts...).Parse(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, "POST", actual.Method)
+			require.Equal(t, tt.body, actual.Body)
+			require.Equal(t, tt.file, actual.BodyFile)
+			require.Equal(t, tt.kind, actual.BodyKind)
+		})
+	}
+
+	_, err := Parse(`curl -d a=1 --data-binary @payload.bin https://api.sloths.com`)
+	require.ErrorIs(t, err, ErrDataFileCombined)
+	_, err = Parse(`curl --data-binary @payload.bin -d a=1 https://api.sloths.com`)
+	require.ErrorIs(t, err, ErrDataFileCombined)
+	_, err = NewParser(WithFileReader(files)).Parse(`curl --data-binary @missing.bin https://api.sloths.com`)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDataFileSend(t *testing.T) {
+	payload := filepath.Join(t.TempDir(), "payload.bin")
+	require.NoError(t, os.WriteFile(payload, []byte("\x00\x01\n\x02"), 0o644))
+
+	req, err := Parse(`curl --data-binary @` + payload + ` https://api.sloths.com`)
+	require.NoError(t, err)
+	require.Equal(t, payload, req.BodyFile)
+
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	body, err := io.ReadAll(hreq.Body)
+	require.NoError(t, err)
+	require.Equal(t, "\x00\x01\n\x02", string(body))
+
+	_, size := req.WireSize()
+	require.Equal(t, int64(4), size)
+
+	again, err := Parse(req.curl())
+	require.NoError(t, err)
+	require.Equal(t, req, again)
+
+	req, err = NewParser(WithFileReader(OSFileReader)).Parse(`curl --data-binary @` + payload + ` https://api.sloths.com`)
+	require.NoError(t, err)
+	require.Equal(t, "\x00\x01\n\x02", req.Body)
+}
+
+func TestParseDataURLEncode(t *testing.T) {
+	files := fstest.MapFS{"query.txt": {Data: []byte("name:sid & co\n")}}
+	var tests = []struct {
+		name  string
+		given string
+		body  string
+	}{
+		{"content", `curl --data-urlencode 'hello world' https://api.sloths.com`, "hello%20world"},
+		{"leading equal", `curl --data-urlencode '=a=b&c' https://api.sloths.com`, "a%3Db%26c"},
+		{"name", `curl --data-urlencode 'q=tree sloth+café' https://api.sloths.com`, "q=tree%20sloth%2Bcaf%C3%A9"},
+		{"unreserved kept", `curl --data-urlencode 'v=a-b.c_d~e' https://api.sloths.com`, "v=a-b.c_d~e"},
+		{"file", `curl --data-urlencode @query.txt https://api.sloths.com`, "name%3Asid%20%26%20co%0A"},
+		{"named file", `curl --data-urlencode q@query.txt https://api.sloths.com`, "q=name%3Asid%20%26%20co%0A"},
+		{"mixed", `curl -d page=2 --data-urlencode 'q=a b' https://api.sloths.com`, "page=2&q=a%20b"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewParser(WithFileReader(files)).Parse(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, "POST", actual.Method)
+			require.Equal(t, tt.body, actual.Body)
+		})
+	}
+
+	_, err := Parse(`curl --data-urlencode q@query.txt https://api.sloths.com`)
+	require.ErrorIs(t, err, ErrNoFileReader)
+}
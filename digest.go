@@ -0,0 +1,186 @@
+package gcurl
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Digest algorithms of RFC 9530.
+const (
+	DigestSHA256 = "sha-256"
+	DigestSHA512 = "sha-512"
+)
+
+// ErrDigestMismatch is returned when a response body doesn't match its
+// Content-Digest or Digest header.
+var ErrDigestMismatch = errors.New("content digest mismatch")
+
+var digestHashes = map[string]func() hash.Hash{
+	DigestSHA256: sha256.New,
+	DigestSHA512: sha512.New,
+}
+
+// ContentDigest returns the RFC 9530 Content-Digest value of body for the
+// given algorithms, sha-256 if none.
+func ContentDigest(body []byte, algs ...string) (string, error) {
+	if len(algs) == 0 {
+		algs = []string{DigestSHA256}
+	}
+	parts := make([]string, 0, len(algs))
+	for _, alg := range algs {
+		sum, err := digestSum(alg, body)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, strings.ToLower(alg)+"=:"+sum+":")
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+func digestSum(alg string, body []byte) (string, error) {
+	hf, ok := digestHashes[strings.ToLower(alg)]
+	if !ok {
+		return "", fmt.Errorf("unsupported digest algorithm %q", alg)
+	}
+	h := hf()
+	h.Write(body)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// SetContentDigest sets the Content-Digest header from the body, read
+// from BodyFile or UploadFile if needed. Multipart bodies are only built
+// when sent, with a random boundary: digest them with ContentDigester.
+func (r *Request) SetContentDigest(algs ...string) error {
+	var body []byte
+	switch {
+	case len(r.Form) > 0:
+		return errors.New("content digest: multipart body is built when sent, use ContentDigester")
+	case r.UploadFile == "-", r.BodyFile == "-":
+		return errors.New("content digest: can't read the body from standard input")
+	case r.Body != "":
+		body = []byte(r.Body)
+	case r.BodyFile != "", r.UploadFile != "":
+		file := r.BodyFile
+		if file == "" {
+			file = r.UploadFile
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("content digest: %w", err)
+		}
+		body = b
+	}
+	v, err := ContentDigest(body, algs...)
+	if err != nil {
+		return fmt.Errorf("content digest: %w", err)
+	}
+	r.Header.Set("content-digest", v)
+	return nil
+}
+
+// ContentDigester is a PreSendHook setting Content-Digest from the body
+// as sent, and with Legacy also the RFC 3230 Digest header.
+type ContentDigester struct {
+	// Algorithms defaults to sha-256.
+	Algorithms []string
+	Legacy     bool
+}
+
+func (d *ContentDigester) BeforeSend(req *http.Request) error {
+	body, err := requestBody(req)
+	if err != nil {
+		return err
+	}
+	v, err := ContentDigest(body, d.Algorithms...)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Digest", v)
+	if d.Legacy {
+		req.Header.Set("Digest", legacyDigest(v))
+	}
+	return nil
+}
+
+// legacyDigest converts a Content-Digest value to the Digest syntax,
+// e.g. "SHA-256=<base64>".
+func legacyDigest(contentDigest string) string {
+	var parts []string
+	for alg, sum := range parseDigests(contentDigest, false) {
+		parts = append(parts, strings.ToUpper(alg)+"="+sum)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// parseDigests parses Content-Digest, or Digest when legacy is set, into
+// base64 digests by lower-cased algorithm.
+func parseDigests(header string, legacy bool) map[string]string {
+	digests := map[string]string{}
+	for _, item := range strings.Split(header, ",") {
+		alg, sum, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			continue
+		}
+		if !legacy {
+			if len(sum) < 2 || sum[0] != ':' || sum[len(sum)-1] != ':' {
+				continue
+			}
+			sum = sum[1 : len(sum)-1]
+		}
+		digests[strings.ToLower(strings.TrimSpace(alg))] = sum
+	}
+	return digests
+}
+
+// VerifyContentDigest checks the body against the Content-Digest header,
+// or the legacy Digest header, for every supported algorithm they list.
+// Responses without a digest pass, as do bodies decompressed by the
+// transport, whose digest covers the compressed bytes.
+func (r *Response) VerifyContentDigest() error {
+	if r.Response == nil || r.Uncompressed {
+		return nil
+	}
+	digests := parseDigests(r.Header.Get("Content-Digest"), false)
+	if len(digests) == 0 {
+		digests = parseDigests(r.Header.Get("Digest"), true)
+	}
+	for alg, want := range digests {
+		got, err := digestSum(alg, r.body)
+		if err != nil {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+			return fmt.Errorf("%w: %s", ErrDigestMismatch, alg)
+		}
+	}
+	return nil
+}
+
+// WithDigestVerification fails requests whose response body doesn't match
+// its Content-Digest, with ErrDigestMismatch.
+func WithDigestVerification() ExecOption {
+	return func(c *execConfig) { c.verifyDigest = true }
+}
+
+func verifyingSend(next func(*http.Client, *http.Request) (*Response, error)) func(*http.Client, *http.Request) (*Response, error) {
+	return func(client *http.Client, hreq *http.Request) (*Response, error) {
+		resp, err := next(client, hreq)
+		if err != nil {
+			return nil, err
+		}
+		if err := resp.VerifyContentDigest(); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,76 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentDigest(t *testing.T) {
+	// RFC 9530 Appendix D.1.
+	v, err := ContentDigest([]byte(`{"hello": "world"}`+"\n"), DigestSHA256, DigestSHA512)
+	require.NoError(t, err)
+	require.Equal(t, "sha-256=:RK/0qy18MlBSVnWgjwz6lZEWjP/lF5HF9bvEF8FabDg=:, "+
+		"sha-512=:YMAam51Jz/jOATT6/zvHrLVgOYTGFy1d6GJiOHTohq4yP+pgk4vf2aCsyRZOtw8MjkM7iw7yZ/WkppmM44T3qg==:", v)
+
+	_, err = ContentDigest(nil, "md5")
+	require.EqualError(t, err, `unsupported digest algorithm "md5"`)
+}
+
+func TestSetContentDigest(t *testing.T) {
+	req, err := Parse(`curl -d '{"hello": "world"}' https://api.site.com`)
+	require.NoError(t, err)
+	require.NoError(t, req.SetContentDigest())
+	require.Equal(t, "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:", req.Header["content-digest"])
+
+	file := filepath.Join(t.TempDir(), "body.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"hello": "world"}`), 0o644))
+	req, err = Parse("curl -T " + file + " https://api.site.com/files/")
+	require.NoError(t, err)
+	require.NoError(t, req.SetContentDigest())
+	require.Equal(t, "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:", req.Header["content-digest"])
+
+	req, err = Parse("curl -F a=1 https://api.site.com")
+	require.NoError(t, err)
+	require.Error(t, req.SetContentDigest())
+}
+
+func TestContentDigester(t *testing.T) {
+	req, err := (&Request{Method: http.MethodPost, URL: "https://api.site.com", Header: Header{}, Body: `{"hello": "world"}`}).ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, (&ContentDigester{Legacy: true}).BeforeSend(req))
+	require.Equal(t, "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:", req.Header.Get("Content-Digest"))
+	require.Equal(t, "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=", req.Header.Get("Digest"))
+}
+
+func TestDigestVerification(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/good":
+			w.Header().Set("Content-Digest", "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:, md5=:unknown:")
+		case "/legacy":
+			w.Header().Set("Digest", "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=")
+		case "/bad":
+			w.Header().Set("Content-Digest", "sha-256=:RK/0qy18MlBSVnWgjwz6lZEWjP/lF5HF9bvEF8FabDg=:")
+		}
+		w.Write([]byte(`{"hello": "world"}`))
+	}))
+	defer srv.Close()
+
+	for _, path := range []string{"/good", "/legacy", "/none"} {
+		_, err := Do(context.Background(), "curl "+srv.URL+path, WithDigestVerification())
+		require.NoError(t, err, path)
+	}
+
+	_, err := Do(context.Background(), "curl "+srv.URL+"/bad", WithDigestVerification())
+	require.ErrorIs(t, err, ErrDigestMismatch)
+
+	_, err = Do(context.Background(), "curl "+srv.URL+"/bad")
+	require.NoError(t, err)
+}
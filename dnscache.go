@@ -0,0 +1,128 @@
+package gcurl
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultDNSCacheTTL is used when DNSCache.TTL is zero.
+const DefaultDNSCacheTTL = time.Minute
+
+// HostResolver looks up the addresses of a host. *net.Resolver implements it.
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// DNSCache caches host lookups so that replaying many requests against the
+// same hosts resolves each of them once per TTL. It is safe for concurrent
+// use and meant to be shared by the transports of a batch run.
+type DNSCache struct {
+	TTL      time.Duration
+	Resolver HostResolver
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+	hits    int64
+	misses  int64
+}
+
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// DNSCacheStats reports the cache effectiveness.
+type DNSCacheStats struct {
+	Hits    int64
+	Misses  int64
+	Entries int
+}
+
+func NewDNSCache(ttl time.Duration) *DNSCache {
+	return &DNSCache{TTL: ttl}
+}
+
+// LookupHost returns the cached addresses of host, looking them up when
+// missing or expired. Expiry is measured with the executor's clock, see
+// WithClock.
+func (c *DNSCache) LookupHost(ctx context.Context, host string) ([]string, error) {
+	now := clockNow(ctx)
+
+	c.mu.Lock()
+	if e, ok := c.entries[host]; ok && now.Before(e.expires) {
+		c.hits++
+		c.mu.Unlock()
+		return e.addrs, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	resolver := c.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := c.TTL
+	if ttl == 0 {
+		ttl = DefaultDNSCacheTTL
+	}
+
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[string]dnsEntry{}
+	}
+	c.entries[host] = dnsEntry{addrs: addrs, expires: now.Add(ttl)}
+	c.mu.Unlock()
+	return addrs, nil
+}
+
+// DialContext returns a dial function for http.Transport that resolves
+// hosts through the cache, trying each cached address in turn.
+func (c *DNSCache) DialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		addrs, err := c.LookupHost(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for _, ip := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// Flush drops all cached entries.
+func (c *DNSCache) Flush() {
+	c.mu.Lock()
+	c.entries = nil
+	c.mu.Unlock()
+}
+
+func (c *DNSCache) Stats() DNSCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return DNSCacheStats{Hits: c.hits, Misses: c.misses, Entries: len(c.entries)}
+}
@@ -0,0 +1,94 @@
+package gcurl
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver struct {
+	calls int
+	addrs map[string][]string
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	r.calls++
+	if addrs, ok := r.addrs[host]; ok {
+		return addrs, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func TestDNSCacheLookupHost(t *testing.T) {
+	resolver := &fakeResolver{addrs: map[string][]string{"api.site.com": {"10.0.0.1"}}}
+	c := &DNSCache{TTL: time.Hour, Resolver: resolver}
+
+	for i := 0; i < 3; i++ {
+		addrs, err := c.LookupHost(context.Background(), "api.site.com")
+		require.NoError(t, err)
+		require.Equal(t, []string{"10.0.0.1"}, addrs)
+	}
+	_, err := c.LookupHost(context.Background(), "unknown.site.com")
+	require.Error(t, err)
+
+	require.Equal(t, 2, resolver.calls)
+	require.Equal(t, DNSCacheStats{Hits: 2, Misses: 2, Entries: 1}, c.Stats())
+
+	c.Flush()
+	require.Equal(t, 0, c.Stats().Entries)
+}
+
+func TestDNSCacheExpiry(t *testing.T) {
+	resolver := &fakeResolver{addrs: map[string][]string{"api.site.com": {"10.0.0.1"}}}
+	c := &DNSCache{TTL: time.Nanosecond, Resolver: resolver}
+
+	_, _ = c.LookupHost(context.Background(), "api.site.com")
+	time.Sleep(time.Millisecond)
+	_, _ = c.LookupHost(context.Background(), "api.site.com")
+	require.Equal(t, 2, resolver.calls)
+}
+
+func TestDNSCacheDialContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	resolver := &fakeResolver{addrs: map[string][]string{"sloth.test": {"127.0.0.1"}}}
+	c := &DNSCache{Resolver: resolver}
+	client := &http.Client{Transport: &http.Transport{DialContext: c.DialContext(nil), DisableKeepAlives: true}}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get("http://sloth.test:" + port + "/")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.Equal(t, 1, resolver.calls)
+	require.Equal(t, int64(1), c.Stats().Hits)
+}
+
+func TestDNSCacheClock(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	resolver := &fakeResolver{addrs: map[string][]string{"sloth.test": {"127.0.0.1"}}}
+	c := &DNSCache{TTL: time.Minute, Resolver: resolver}
+	clock := &stubClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	opts := []ExecOption{WithDNSCache(c), WithClock(clock), WithDisableKeepAlives()}
+
+	for _, elapsed := range []time.Duration{0, 30 * time.Second, time.Minute} {
+		clock.now = clock.now.Add(elapsed)
+		_, err := Do(context.Background(), "curl http://sloth.test:"+port+"/", opts...)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, resolver.calls)
+}
@@ -0,0 +1,81 @@
+package gcurl
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// DryRunReport describes what would have been sent by a dry run.
+type DryRunReport struct {
+	Method   string
+	URL      string
+	Header   http.Header
+	BodySize int64
+	// Addrs are the resolved addresses of the target host.
+	Addrs              []string
+	TLS                bool
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+	Warnings           []string
+}
+
+// DryRun makes execution build the client and request, resolve the host
+// and validate the TLS setup without sending anything. The returned
+// Response carries no status or body; its DryRun field holds the report.
+func DryRun() ExecOption {
+	return func(c *execConfig) { c.dryRun = true }
+}
+
+// dryRun reports timeout, the request's, since client has none when a
+// clock enforces it.
+func (r *Request) dryRun(ctx context.Context, client *http.Client, hreq *http.Request, timeout time.Duration, cfg *execConfig) (*Response, error) {
+	report := &DryRunReport{
+		Method:   hreq.Method,
+		URL:      hreq.URL.String(),
+		Header:   hreq.Header.Clone(),
+		BodySize: hreq.ContentLength,
+		TLS:      hreq.URL.Scheme == "https",
+		Timeout:  timeout,
+	}
+
+	host := hreq.URL.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		report.Addrs = []string{ip.String()}
+	} else {
+		var resolver HostResolver = net.DefaultResolver
+		if cfg.dnsCache != nil {
+			resolver = cfg.dnsCache
+		}
+		addrs, err := resolver.LookupHost(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("dry run: %w", err)
+		}
+		report.Addrs = addrs
+	}
+
+	if t, ok := client.Transport.(*http.Transport); ok && t.TLSClientConfig != nil {
+		report.InsecureSkipVerify = t.TLSClientConfig.InsecureSkipVerify
+	}
+	switch {
+	case !report.TLS && r.SkipTLS:
+		report.Warnings = append(report.Warnings, "--insecure has no effect on a plain HTTP URL")
+	case report.TLS && report.InsecureSkipVerify:
+		report.Warnings = append(report.Warnings, "TLS certificate verification is disabled")
+	case report.TLS:
+		if _, err := x509.SystemCertPool(); err != nil {
+			return nil, fmt.Errorf("dry run: loading system roots: %w", err)
+		}
+	}
+
+	resp := &http.Response{
+		Status:  "dry run",
+		Header:  http.Header{},
+		Body:    http.NoBody,
+		Request: hreq,
+	}
+	return &Response{Response: resp, DryRun: report}, nil
+}
@@ -0,0 +1,53 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+
+	resp, err := Do(context.Background(), `curl -k -m 2 -H 'X-Sloth: sid' -d 'name=sid' `+srv.URL, DryRun())
+	require.NoError(t, err)
+	require.Equal(t, 0, hits)
+	require.Equal(t, 0, resp.StatusCode)
+
+	report := resp.DryRun
+	require.NotNil(t, report)
+	require.Equal(t, "POST", report.Method)
+	require.Equal(t, srv.URL, report.URL)
+	require.Equal(t, "sid", report.Header.Get("X-Sloth"))
+	require.Equal(t, int64(8), report.BodySize)
+	require.Equal(t, []string{"127.0.0.1"}, report.Addrs)
+	require.False(t, report.TLS)
+	require.Equal(t, 2*time.Second, report.Timeout)
+	require.Equal(t, []string{"--insecure has no effect on a plain HTTP URL"}, report.Warnings)
+}
+
+func TestDryRunClock(t *testing.T) {
+	resp, err := Do(context.Background(), "curl -m 5 http://127.0.0.1/sloths", DryRun(), WithClock(stubClock{}))
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, resp.DryRun.Timeout)
+}
+
+func TestDryRunResolvesThroughCache(t *testing.T) {
+	cache := &DNSCache{Resolver: &fakeResolver{addrs: map[string][]string{"api.site.com": {"10.0.0.1"}}}}
+
+	resp, err := Do(context.Background(), "curl -k https://api.site.com/sloths", DryRun(), WithDNSCache(cache))
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1"}, resp.DryRun.Addrs)
+	require.True(t, resp.DryRun.TLS)
+	require.True(t, resp.DryRun.InsecureSkipVerify)
+	require.Equal(t, []string{"TLS certificate verification is disabled"}, resp.DryRun.Warnings)
+
+	_, err = Do(context.Background(), "curl https://unknown.site.com", DryRun(), WithDNSCache(cache))
+	require.ErrorContains(t, err, "dry run:")
+}
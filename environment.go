@@ -0,0 +1,124 @@
+package gcurl
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Environment is a profile such as dev, staging or prod merged into
+// catalog requests at render time, so one stored command serves all of
+// them.
+type Environment struct {
+	Name string `json:"name"`
+	// BaseURL replaces the scheme and host of request URLs. Its path, if
+	// any, is prefixed to the request path.
+	BaseURL string `json:"base_url,omitempty"`
+	// Header holds default headers, added unless the request sets them.
+	Header Header `json:"header,omitempty"`
+	// Variables fill {{name}} placeholders. Variables given to RenderEnv
+	// take precedence.
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// Apply returns a copy of req with the environment merged in, vars taking
+// precedence over the environment variables.
+func (e *Environment) Apply(req *Request, vars map[string]string) (*Request, error) {
+	merged := make(map[string]string, len(e.Variables)+len(vars))
+	for k, v := range e.Variables {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+
+	res := renderRequest(req, merged)
+	for k, v := range e.Header {
+		k = strings.ToLower(k)
+		if _, ok := res.Header[k]; !ok {
+			res.Header[k] = expandPlaceholders(v, merged)
+		}
+	}
+
+	if e.BaseURL != "" {
+		u, err := rebaseURL(res.URL, expandPlaceholders(e.BaseURL, merged))
+		if err != nil {
+			return nil, fmt.Errorf("environment %q: %w", e.Name, err)
+		}
+		res.URL = u
+	}
+	return res, nil
+}
+
+func rebaseURL(rawURL, baseURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme, u.Host = base.Scheme, base.Host
+	if base.User != nil {
+		u.User = base.User
+	}
+	if prefix := strings.TrimSuffix(base.EscapedPath(), "/"); prefix != "" {
+		path := prefix + "/" + strings.TrimPrefix(u.EscapedPath(), "/")
+		if u.Path, err = url.PathUnescape(path); err != nil {
+			return "", err
+		}
+		u.RawPath = path
+	}
+	return u.String(), nil
+}
+
+// AddEnvironment stores env in the catalog, replacing any environment
+// with the same name.
+func (c *Catalog) AddEnvironment(env *Environment) error {
+	if env.Name == "" {
+		return fmt.Errorf("catalog: environment without name")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.environments == nil {
+		c.environments = map[string]*Environment{}
+	}
+	c.environments[env.Name] = env
+	return nil
+}
+
+func (c *Catalog) Environment(name string) (*Environment, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	env, ok := c.environments[name]
+	return env, ok
+}
+
+// Environments returns all environments sorted by name.
+func (c *Catalog) Environments() []*Environment {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]*Environment, 0, len(c.environments))
+	for _, env := range c.environments {
+		res = append(res, env)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// RenderEnv is like Render but merges the named environment into the
+// request first.
+func (c *Catalog) RenderEnv(name, env string, vars map[string]string) (*Request, error) {
+	e, ok := c.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("catalog: no entry %q", name)
+	}
+	profile, ok := c.Environment(env)
+	if !ok {
+		return nil, fmt.Errorf("catalog: no environment %q", env)
+	}
+	return profile.Apply(e.Request, vars)
+}
@@ -0,0 +1,84 @@
+package gcurl
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentApply(t *testing.T) {
+	req, err := Parse(`curl -H 'Authorization: Bearer {{token}}' -H 'Accept: text/plain' 'https://api.site.com/sloths/{{id}}?page=2'`)
+	require.NoError(t, err)
+
+	var tests = []struct {
+		name     string
+		env      *Environment
+		vars     map[string]string
+		url      string
+		expected Header
+	}{
+		{
+			"variables and default headers",
+			&Environment{
+				Name:      "dev",
+				Header:    Header{"Accept": "*/*", "X-Env": "{{env}}"},
+				Variables: map[string]string{"token": "dev-token", "id": "1", "env": "dev"},
+			},
+			map[string]string{"id": "4"},
+			"https://api.site.com/sloths/4?page=2",
+			Header{"authorization": "Bearer dev-token", "accept": "text/plain", "x-env": "dev"},
+		},
+		{
+			"base URL",
+			&Environment{Name: "staging", BaseURL: "http://localhost:8080"},
+			nil,
+			"http://localhost:8080/sloths/%7B%7Bid%7D%7D?page=2",
+			Header{"authorization": "Bearer {{token}}", "accept": "text/plain"},
+		},
+		{
+			"base URL with path prefix",
+			&Environment{Name: "prod", BaseURL: "https://{{region}}.site.com/api/v2/", Variables: map[string]string{"region": "eu", "id": "a b"}},
+			nil,
+			"https://eu.site.com/api/v2/sloths/a%20b?page=2",
+			Header{"authorization": "Bearer {{token}}", "accept": "text/plain"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.env.Apply(req, tt.vars)
+			require.NoError(t, err)
+			require.Equal(t, tt.url, actual.URL)
+			require.Equal(t, tt.expected, actual.Header)
+		})
+	}
+	require.Equal(t, "https://api.site.com/sloths/{{id}}?page=2", req.URL)
+}
+
+func TestCatalogRenderEnv(t *testing.T) {
+	c := newTestCatalog(t)
+	require.NoError(t, c.AddEnvironment(&Environment{
+		Name:      "staging",
+		BaseURL:   "https://staging.site.com",
+		Variables: map[string]string{"host": "ignored.site.com", "token": "s", "id": "1"},
+	}))
+	require.Error(t, c.AddEnvironment(&Environment{}))
+
+	req, err := c.RenderEnv("get-sloth", "staging", map[string]string{"id": "7"})
+	require.NoError(t, err)
+	require.Equal(t, "https://staging.site.com/sloths/7", req.URL)
+	require.Equal(t, "Bearer s", req.Header[KeyAuthorization])
+
+	_, err = c.RenderEnv("get-sloth", "missing", nil)
+	require.Error(t, err)
+	_, err = c.RenderEnv("missing", "staging", nil)
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "catalog.yml")
+	require.NoError(t, c.Save(path))
+	loaded, err := LoadCatalog(path)
+	require.NoError(t, err)
+	require.Equal(t, c.Environments(), loaded.Environments())
+}
@@ -0,0 +1,56 @@
+package gcurl
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ParseEscaped parses a curl command embedded in a JSON log with the
+// default Parser, see Parser.ParseEscaped.
+func ParseEscaped(curl string) (*Request, error) {
+	return defaultParser.ParseEscaped(curl)
+}
+
+// ParseEscaped parses a command that went through JSON or backslash
+// escaping, as found in JSON logs: either a whole JSON string literal,
+// quotes included, or its contents with \" and \\ escapes.
+func (p *Parser) ParseEscaped(curl string) (*Request, error) {
+	return p.Parse(unescapeCommand(curl))
+}
+
+func unescapeCommand(s string) string {
+	s = strings.TrimSpace(s)
+	var u string
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if json.Unmarshal([]byte(s), &u) == nil {
+			return strings.TrimSpace(u)
+		}
+	}
+	// A bare double quote means the command was not escaped.
+	if !strings.Contains(s, `\`) || hasBareQuote(s) {
+		return s
+	}
+	if json.Unmarshal([]byte(`"`+s+`"`), &u) == nil {
+		return u
+	}
+	// Not valid JSON, e.g. because of a \' escape: undo the common
+	// escapes only.
+	return strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(s)
+}
+
+// hasBareQuote reports whether s has a double quote not escaped by a
+// backslash.
+func hasBareQuote(s string) bool {
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			return true
+		}
+	}
+	return false
+}
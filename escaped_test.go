@@ -0,0 +1,52 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEscaped(t *testing.T) {
+	expected := &Request{
+		Method:   "POST",
+		URL:      "https://api.site.com/sloths",
+		Header:   Header{"content-type": "application/json"},
+		Body:     `{"name":"sid","quote":"it's \"ok\""}`,
+		BodyKind: BodyJSON,
+	}
+
+	var tests = []struct {
+		name string
+		curl string
+	}{
+		{
+			"JSON string literal",
+			`"curl -H \"Content-Type: application/json\" -d '{\"name\":\"sid\",\"quote\":\"it'\\''s \\\"ok\\\"\"}' https://api.site.com/sloths"`,
+		},
+		{
+			"escaped contents",
+			`curl -H \"Content-Type: application/json\" -d '{\"name\":\"sid\",\"quote\":\"it'\\''s \\\"ok\\\"\"}' https://api.site.com/sloths`,
+		},
+		{
+			"escaped newlines",
+			`"curl -X POST \\\n  -H 'Content-Type: application/json' \\\n  -d '{\"name\":\"sid\",\"quote\":\"it'\\''s \\\"ok\\\"\"}' \\\n  https://api.site.com/sloths"`,
+		},
+		{
+			"mixed quotes",
+			`curl -H "Content-Type: application/json" -d "{\"name\":\"sid\",\"quote\":\"it's \\\"ok\\\"\"}" https://api.site.com/sloths`,
+		},
+		{
+			"plain command",
+			`curl -H 'Content-Type: application/json' -d '{"name":"sid","quote":"it'\''s \"ok\""}' https://api.site.com/sloths`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseEscaped(tt.curl)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		})
+	}
+}
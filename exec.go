@@ -0,0 +1,380 @@
+package gcurl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExecOption configures how requests are executed.
+type ExecOption func(*execConfig)
+
+type execConfig struct {
+	transport http.RoundTripper
+	redirect  *RedirectPolicy
+	dnsCache  *DNSCache
+	jar       http.CookieJar
+	hooks     []PreSendHook
+	auth      AuthProvider
+	urlPolicy *URLPolicy
+	cache     ResponseCache
+	clock     Clock
+	random    io.Reader
+	rewrites  []RewriteRule
+	secrets   SecretProvider
+
+	headerRules []HeaderRule
+
+	sessionCookies bool
+	sigv4          bool
+	dryRun         bool
+	verifyDigest   bool
+
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+	idleConnTimeout     time.Duration
+	disableKeepAlives   bool
+
+	// Transports are built lazily and shared by all requests executed
+	// with the same config, keyed by the request's TLS and proxy settings.
+	mu         sync.Mutex
+	transports map[transportKey]*http.Transport
+}
+
+type transportKey struct {
+	skipTLS          bool
+	connectTimeout   time.Duration
+	proxy, proxyAuth string
+	noProxy          string
+}
+
+func newExecConfig(opts []ExecOption) *execConfig {
+	cfg := &execConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithTransport sends requests through rt. Transport tuning options and
+// the request's TLS settings are ignored when a custom transport is used.
+func WithTransport(rt http.RoundTripper) ExecOption {
+	return func(c *execConfig) { c.transport = rt }
+}
+
+func WithRedirectPolicy(p *RedirectPolicy) ExecOption {
+	return func(c *execConfig) { c.redirect = p }
+}
+
+// WithDNSCache resolves hosts through a shared DNS cache.
+func WithDNSCache(cache *DNSCache) ExecOption {
+	return func(c *execConfig) { c.dnsCache = cache }
+}
+
+// WithCookieJar stores and sends cookies through jar.
+func WithCookieJar(jar http.CookieJar) ExecOption {
+	return func(c *execConfig) { c.jar = jar }
+}
+
+// WithSessionCookies gives each pipeline run its own cookie jar, so cookies
+// set by one step are sent by the following ones.
+func WithSessionCookies() ExecOption {
+	return func(c *execConfig) { c.sessionCookies = true }
+}
+
+// WithMaxIdleConns limits idle keep-alive connections across all hosts.
+func WithMaxIdleConns(n int) ExecOption {
+	return func(c *execConfig) { c.maxIdleConns = n }
+}
+
+// WithMaxIdleConnsPerHost limits idle keep-alive connections per host.
+// net/http defaults to 2, which is usually too low for replays.
+func WithMaxIdleConnsPerHost(n int) ExecOption {
+	return func(c *execConfig) { c.maxIdleConnsPerHost = n }
+}
+
+// WithMaxConnsPerHost limits connections per host, including those in use.
+func WithMaxConnsPerHost(n int) ExecOption {
+	return func(c *execConfig) { c.maxConnsPerHost = n }
+}
+
+func WithIdleConnTimeout(d time.Duration) ExecOption {
+	return func(c *execConfig) { c.idleConnTimeout = d }
+}
+
+func WithDisableKeepAlives() ExecOption {
+	return func(c *execConfig) { c.disableKeepAlives = true }
+}
+
+// defaultExecConfig executes the requests given no option, so that their
+// transports and keep-alive connections are shared between calls.
+var defaultExecConfig = &execConfig{}
+
+// Do parses cmd and executes it, returning the response with its body read.
+func Do(ctx context.Context, cmd string, opts ...ExecOption) (*Response, error) {
+	req, err := ParseContext(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return req.Execute(ctx, opts...)
+}
+
+// Execute sends the request with a client honoring its SkipTLS, Timeout,
+// proxy and redirect settings and returns the response with its body
+// read. The embedded *http.Response is the one received. Calls without
+// options share keep-alive connections. With options, the connections of
+// the call are closed once it returns: use a BatchRunner or RunPipeline
+// to reuse them across requests.
+func (r *Request) Execute(ctx context.Context, opts ...ExecOption) (*Response, error) {
+	if len(opts) == 0 {
+		return r.do(ctx, defaultExecConfig)
+	}
+	cfg := newExecConfig(opts)
+	defer cfg.closeIdleConnections()
+	return r.do(ctx, cfg)
+}
+
+func (r *Request) do(ctx context.Context, cfg *execConfig) (*Response, error) {
+	client, err := cfg.newClient(r)
+	if err != nil {
+		return nil, err
+	}
+	// The timeout is kept for dry runs, the clock taking over from client.
+	timeout := client.Timeout
+	if cfg.clock != nil {
+		ctx = contextWithClock(ctx, cfg.clock)
+		var cancel context.CancelFunc
+		ctx, cancel = cfg.withTimeout(ctx, client.Timeout)
+		defer cancel()
+		client.Timeout = 0
+	}
+	hreq, err := r.prepare(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.dryRun {
+		return r.dryRun(ctx, client, hreq, timeout, cfg)
+	}
+
+	send := cfg.cachedSend(r)
+	if cfg.verifyDigest {
+		send = verifyingSend(send)
+	}
+	if r.Compressed {
+		send = decompressingSend(send)
+	}
+	resp, err := send(client, hreq)
+	if err != nil {
+		if cfg.clock != nil && ctx.Err() != nil {
+			// Report the clock timeout rather than a bare cancellation.
+			return nil, context.Cause(ctx)
+		}
+		return nil, err
+	}
+
+	// A rejected token may have been revoked before its expiry: fetch a
+	// fresh one and try once more.
+	if inv, ok := cfg.auth.(TokenInvalidator); ok && resp.StatusCode == http.StatusUnauthorized {
+		inv.Invalidate()
+		if hreq, err = r.prepare(ctx, cfg); err != nil {
+			return nil, err
+		}
+		return send(client, hreq)
+	}
+	return resp, nil
+}
+
+// prepare builds the outgoing request and applies credentials and hooks.
+func (r *Request) prepare(ctx context.Context, cfg *execConfig) (*http.Request, error) {
+	r, err := cfg.withBoundary(r)
+	if err != nil {
+		return nil, err
+	}
+	hreq, err := r.toHTTPRequest(ctx, cfg.now())
+	if err != nil {
+		return nil, err
+	}
+	captured := *hreq.URL
+	if rewrite(cfg.rewrites, hreq.URL) && !r.Header.Has("host") {
+		hreq.Host = ""
+	}
+	if err := cfg.applyHeaderRules(ctx, hreq, &captured); err != nil {
+		return nil, err
+	}
+	if cfg.urlPolicy != nil {
+		if err := cfg.urlPolicy.check(hreq.URL, r.URL); err != nil {
+			return nil, err
+		}
+		if err := cfg.urlPolicy.checkProxy(r); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.auth != nil {
+		if err := cfg.auth.Authorize(ctx, hreq); err != nil {
+			return nil, err
+		}
+	}
+	for _, h := range cfg.hooks {
+		if err := h.BeforeSend(hreq); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.signSigV4(r, hreq); err != nil {
+		return nil, err
+	}
+	return hreq, nil
+}
+
+func send(client *http.Client, hreq *http.Request) (*Response, error) {
+	resp, err := client.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return &Response{Response: resp, body: body}, nil
+}
+
+// NewTransport returns an http.Transport built from the default transport
+// with the tuning options applied.
+func NewTransport(opts ...ExecOption) *http.Transport {
+	return newExecConfig(opts).newTransport(false, 0)
+}
+
+func (c *execConfig) newTransport(skipTLS bool, connectTimeout time.Duration) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if c.maxIdleConns != 0 {
+		t.MaxIdleConns = c.maxIdleConns
+	}
+	if c.maxIdleConnsPerHost != 0 {
+		t.MaxIdleConnsPerHost = c.maxIdleConnsPerHost
+	}
+	if c.maxConnsPerHost != 0 {
+		t.MaxConnsPerHost = c.maxConnsPerHost
+	}
+	if c.idleConnTimeout != 0 {
+		t.IdleConnTimeout = c.idleConnTimeout
+	}
+	t.DisableKeepAlives = c.disableKeepAlives
+	if c.dnsCache != nil || connectTimeout > 0 {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+		if connectTimeout > 0 {
+			dialer.Timeout = connectTimeout
+			t.TLSHandshakeTimeout = connectTimeout
+		}
+		t.DialContext = dialer.DialContext
+		if c.dnsCache != nil {
+			t.DialContext = c.dnsCache.DialContext(dialer)
+		}
+	}
+	if skipTLS {
+		t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return t
+}
+
+func (c *execConfig) sharedTransport(req *Request) (*http.Transport, error) {
+	key := transportKey{
+		skipTLS:        req.SkipTLS,
+		connectTimeout: req.ConnectTimeout,
+		proxy:          req.Proxy,
+		proxyAuth:      req.ProxyAuth,
+		noProxy:        strings.Join(req.NoProxy, ","),
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.transports[key]; ok {
+		return t, nil
+	}
+
+	t := c.newTransport(req.SkipTLS, req.ConnectTimeout)
+	if req.Proxy != "" {
+		proxy, err := req.ProxyFunc()
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", req.Proxy, err)
+		}
+		t.Proxy = proxy
+	}
+	if c.transports == nil {
+		c.transports = map[transportKey]*http.Transport{}
+	}
+	c.transports[key] = t
+	return t, nil
+}
+
+// closeIdleConnections closes the idle connections of the transports
+// built for the config.
+func (c *execConfig) closeIdleConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.transports {
+		t.CloseIdleConnections()
+	}
+}
+
+// NewClient returns an http.Client configured for req: its TLS, proxy and
+// timeout settings plus the given execution options.
+func NewClient(req *Request, opts ...ExecOption) (*http.Client, error) {
+	return newExecConfig(opts).newClient(req)
+}
+
+func (c *execConfig) newClient(req *Request) (*http.Client, error) {
+	client := &http.Client{Transport: c.transport, Jar: c.jar}
+	if client.Transport == nil {
+		t, err := c.sharedTransport(req)
+		if err != nil {
+			return nil, err
+		}
+		client.Transport = t
+	}
+
+	timeout, err := req.TimeoutDuration()
+	if err != nil {
+		return nil, err
+	}
+	client.Timeout = timeout
+
+	redirect := c.redirect
+	if redirect == nil {
+		redirect = &RedirectPolicy{
+			Follow:       req.FollowRedirects,
+			MaxRedirects: req.MaxRedirects,
+			Trusted:      req.TrustRedirects,
+			AutoReferer:  req.AutoReferer,
+		}
+	}
+	client.CheckRedirect = redirect.CheckRedirect
+	if c.urlPolicy != nil {
+		client.CheckRedirect = c.urlPolicy.checkRedirect(client.CheckRedirect)
+	}
+	return client, nil
+}
+
+// TimeoutDuration returns Timeout, given in curl's decimal seconds, as a
+// duration. It is 0 when the request has no timeout.
+func (r *Request) TimeoutDuration() (time.Duration, error) {
+	if r.Timeout == "" {
+		return 0, nil
+	}
+	timeout, err := ParseDuration(r.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", r.Timeout, err)
+	}
+	return timeout, nil
+}
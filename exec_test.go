@@ -0,0 +1,167 @@
+package gcurl
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransport(t *testing.T) {
+	tr := NewTransport(
+		WithMaxIdleConns(500),
+		WithMaxIdleConnsPerHost(50),
+		WithMaxConnsPerHost(64),
+		WithIdleConnTimeout(15*time.Second),
+		WithDisableKeepAlives(),
+	)
+	require.Equal(t, 500, tr.MaxIdleConns)
+	require.Equal(t, 50, tr.MaxIdleConnsPerHost)
+	require.Equal(t, 64, tr.MaxConnsPerHost)
+	require.Equal(t, 15*time.Second, tr.IdleConnTimeout)
+	require.True(t, tr.DisableKeepAlives)
+
+	def := NewTransport()
+	require.Equal(t, http.DefaultTransport.(*http.Transport).MaxIdleConns, def.MaxIdleConns)
+	require.False(t, def.DisableKeepAlives)
+}
+
+func TestNewClient(t *testing.T) {
+	req, err := Parse("curl -k --max-time 0.5 https://api.site.com")
+	require.NoError(t, err)
+
+	client, err := NewClient(req, WithMaxConnsPerHost(4))
+	require.NoError(t, err)
+	require.Equal(t, 500*time.Millisecond, client.Timeout)
+
+	tr := client.Transport.(*http.Transport)
+	require.True(t, tr.TLSClientConfig.InsecureSkipVerify)
+	require.Equal(t, 4, tr.MaxConnsPerHost)
+
+	custom := &http.Transport{}
+	client, err = NewClient(req, WithTransport(custom))
+	require.NoError(t, err)
+	require.Same(t, custom, client.Transport)
+
+	req.Timeout = "soon"
+	_, err = NewClient(req)
+	require.Error(t, err)
+}
+
+func TestDo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Sloth", r.Header.Get("X-Sloth"))
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	resp, err := Do(context.Background(), `curl -H 'X-Sloth: sid' -d 'name=sid' `+srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "POST", resp.Header.Get("X-Method"))
+	require.Equal(t, "sid", resp.Header.Get("X-Sloth"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "name=sid", string(body))
+
+	_, err = Do(context.Background(), "wget "+srv.URL)
+	require.ErrorIs(t, err, ErrNotValidCurlCommand)
+}
+
+func TestExecute(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Header().Set("X-Method", r.Method)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+
+	req, err := Parse("curl -k -L -X DELETE " + srv.URL + "/old")
+	require.NoError(t, err)
+	resp, err := req.Execute(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "/new", resp.Text())
+
+	req, err = Parse("curl " + srv.URL + "/new")
+	require.NoError(t, err)
+	_, err = req.Execute(context.Background())
+	require.Error(t, err, "certificate must be verified without -k")
+
+	req, err = Parse("curl -k -m 0.05 " + srv.URL + "/slow")
+	require.NoError(t, err)
+	_, err = req.Execute(context.Background())
+	require.ErrorContains(t, err, "Client.Timeout")
+}
+
+func TestExecuteConnections(t *testing.T) {
+	var opened, closed int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			atomic.AddInt32(&opened, 1)
+		case http.StateClosed:
+			atomic.AddInt32(&closed, 1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	req, err := Parse("curl " + srv.URL)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := req.Execute(context.Background())
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&opened))
+
+	_, err = req.Execute(context.Background(), WithMaxIdleConnsPerHost(4))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&opened))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&closed) == 1 }, time.Second, time.Millisecond)
+}
+
+func TestTimeouts(t *testing.T) {
+	req, err := Parse("curl -m 0.5 --connect-timeout 2.5 https://api.site.com")
+	require.NoError(t, err)
+	require.Equal(t, 2500*time.Millisecond, req.ConnectTimeout)
+	timeout, err := req.TimeoutDuration()
+	require.NoError(t, err)
+	require.Equal(t, 500*time.Millisecond, timeout)
+	require.Equal(t, "curl -m 0.5 --connect-timeout 2.5 https://api.site.com", req.String())
+
+	client, err := NewClient(req)
+	require.NoError(t, err)
+	require.Equal(t, 500*time.Millisecond, client.Timeout)
+	tr := client.Transport.(*http.Transport)
+	require.Equal(t, 2500*time.Millisecond, tr.TLSHandshakeTimeout)
+	require.NotNil(t, tr.DialContext)
+
+	req.Timeout = ""
+	timeout, err = req.TimeoutDuration()
+	require.NoError(t, err)
+	require.Zero(t, timeout)
+
+	req.Timeout = "-1"
+	_, err = req.TimeoutDuration()
+	require.EqualError(t, err, `invalid timeout "-1": negative duration`)
+
+	_, err = Parse("curl --connect-timeout soon https://api.site.com")
+	require.ErrorContains(t, err, `invalid --connect-timeout "soon"`)
+}
@@ -0,0 +1,171 @@
+package gcurl
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BrunoExporter writes requests as a Bruno collection: a bruno.json
+// manifest and one .bru file per request, in a directory with ExportDir or
+// a zip archive with Export.
+type BrunoExporter struct {
+	Name string
+}
+
+var _ Exporter = (*BrunoExporter)(nil)
+
+var brunoMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "DELETE": true,
+	"PATCH": true, "OPTIONS": true, "HEAD": true,
+}
+
+func (e *BrunoExporter) ExportDir(dir string, reqs []*Request) error {
+	files, err := e.files(reqs)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.name), f.data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Export writes the collection as a zip archive, which Bruno opens once
+// extracted.
+func (e *BrunoExporter) Export(w io.Writer, reqs []*Request) error {
+	files, err := e.files(reqs)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+type brunoFile struct {
+	name string
+	data []byte
+}
+
+// files renders the manifest and the .bru files of the collection.
+func (e *BrunoExporter) files(reqs []*Request) ([]brunoFile, error) {
+	name := e.Name
+	if name == "" {
+		name = "gcurl"
+	}
+
+	manifest, err := json.MarshalIndent(map[string]interface{}{
+		"version": "1",
+		"name":    name,
+		"type":    "collection",
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files := []brunoFile{{"bruno.json", manifest}}
+
+	for i, req := range reqs {
+		reqName := fmt.Sprintf("%03d %s", i+1, req.Method)
+		data, err := MarshalBru(req, reqName, i+1)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, brunoFile{reqName + ".bru", data})
+	}
+	return files, nil
+}
+
+// MarshalBru renders a single request in the Bruno .bru format.
+func MarshalBru(req *Request, name string, seq int) ([]byte, error) {
+	if !brunoMethods[req.Method] {
+		return nil, fmt.Errorf("bruno: unsupported method %q", req.Method)
+	}
+
+	bodyType := "none"
+	if req.Body != "" {
+		kind := req.BodyKind
+		if kind == BodyNone {
+			kind = DetectBodyKind(req.Header[KeyContentType], req.Body)
+		}
+		bodyType = brunoBodyType(kind)
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "meta {\n  name: %s\n  type: http\n  seq: %d\n}\n\n", name, seq)
+	fmt.Fprintf(buf, "%s {\n  url: %s\n  body: %s\n  auth: none\n}\n", strings.ToLower(req.Method), req.URL, bodyType)
+
+	if len(req.Header) > 0 {
+		buf.WriteString("\nheaders {\n")
+		for _, k := range sortedKeys(req.Header) {
+			fmt.Fprintf(buf, "  %s: %s\n", k, req.Header[k])
+		}
+		buf.WriteString("}\n")
+	}
+
+	switch bodyType {
+	case "none":
+	case "form-urlencoded":
+		buf.WriteString("\nbody:form-urlencoded {\n")
+		for _, pair := range strings.Split(req.Body, "&") {
+			key, val, _ := strings.Cut(pair, "=")
+			if k, err := url.QueryUnescape(key); err == nil {
+				key = k
+			}
+			if v, err := url.QueryUnescape(val); err == nil {
+				val = v
+			}
+			fmt.Fprintf(buf, "  %s: %s\n", key, val)
+		}
+		buf.WriteString("}\n")
+	default:
+		fmt.Fprintf(buf, "\nbody:%s {\n", bodyType)
+		for _, line := range strings.Split(brunoBody(req.Body, bodyType), "\n") {
+			buf.WriteString("  " + line + "\n")
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.Bytes(), nil
+}
+
+func brunoBodyType(kind BodyKind) string {
+	switch kind {
+	case BodyJSON, BodyGraphQL:
+		return "json"
+	case BodyXML:
+		return "xml"
+	case BodyForm:
+		return "form-urlencoded"
+	default:
+		return "text"
+	}
+}
+
+func brunoBody(body, bodyType string) string {
+	if bodyType == "json" {
+		buf := &bytes.Buffer{}
+		if err := json.Indent(buf, []byte(body), "", "  "); err == nil {
+			return buf.String()
+		}
+	}
+	return strings.TrimRight(body, "\n")
+}
@@ -0,0 +1,139 @@
+package gcurl
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalBru(t *testing.T) {
+	var tests = []struct {
+		name     string
+		given    *Request
+		expected string
+	}{
+		{
+			"json body",
+			&Request{
+				Method: "POST",
+				URL:    "https://api.site.com/sloths",
+				Header: Header{"content-type": "application/json"},
+				Body:   `{"name":"Sid"}`,
+			},
+			`meta {
+  name: create
+  type: http
+  seq: 1
+}
+
+post {
+  url: https://api.site.com/sloths
+  body: json
+  auth: none
+}
+
+headers {
+  content-type: application/json
+}
+
+body:json {
+  {
+    "name": "Sid"
+  }
+}
+`,
+		},
+		{
+			"form body",
+			&Request{
+				Method: "POST",
+				URL:    "https://api.site.com/sloths",
+				Header: Header{"content-type": "application/x-www-form-urlencoded"},
+				Body:   "name=Sid&kind=two+toed",
+			},
+			`meta {
+  name: create
+  type: http
+  seq: 1
+}
+
+post {
+  url: https://api.site.com/sloths
+  body: form-urlencoded
+  auth: none
+}
+
+headers {
+  content-type: application/x-www-form-urlencoded
+}
+
+body:form-urlencoded {
+  name: Sid
+  kind: two toed
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := MarshalBru(tt.given, "create", 1)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(actual))
+		})
+	}
+}
+
+func TestMarshalBruUnsupportedMethod(t *testing.T) {
+	_, err := MarshalBru(&Request{Method: "PURGE", URL: "https://api.site.com"}, "purge", 1)
+	require.Error(t, err)
+}
+
+func TestBrunoExporterExportDir(t *testing.T) {
+	dir := t.TempDir()
+	reqs := []*Request{
+		{Method: "GET", URL: "https://api.site.com/sloths", Header: Header{}},
+		{Method: "DELETE", URL: "https://api.site.com/sloths/4", Header: Header{}},
+	}
+	require.NoError(t, (&BrunoExporter{Name: "sloths"}).ExportDir(dir, reqs))
+
+	for _, name := range []string{"bruno.json", "001 GET.bru", "002 DELETE.bru"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err)
+	}
+}
+
+func TestBrunoExporterExport(t *testing.T) {
+	reqs := []*Request{
+		{Method: "GET", URL: "https://api.site.com/sloths", Header: Header{}},
+		{Method: "POST", URL: "https://api.site.com/sloths", Header: Header{"content-type": "application/json"}, Body: `{"name":"Sid"}`},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, (&BrunoExporter{Name: "sloths"}).Export(buf, reqs))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	require.Len(t, files, 3)
+	require.Contains(t, files["bruno.json"], `"name": "sloths"`)
+	post, err := MarshalBru(reqs[1], "002 POST", 2)
+	require.NoError(t, err)
+	require.Equal(t, string(post), files["002 POST.bru"])
+	require.Contains(t, files, "001 GET.bru")
+
+	require.Error(t, (&BrunoExporter{}).Export(io.Discard, []*Request{{Method: "PROPFIND", URL: "https://api.site.com"}}))
+}
@@ -0,0 +1,129 @@
+package gcurl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// GatlingExporter renders requests as a Gatling Java DSL simulation that
+// executes them in sequence, pausing for ThinkTime between each one.
+type GatlingExporter struct {
+	Package    string
+	Simulation string
+	// ThinkTime is the default pause between requests. It can be
+	// overridden at run time with -DthinkTime=<ms>.
+	ThinkTime time.Duration
+}
+
+type gatlingRequest struct {
+	Name    string
+	Method  string
+	URL     string
+	Body    string
+	Headers [][2]string
+}
+
+var gatlingTemplate = template.Must(template.New("gatling").Funcs(template.FuncMap{"q": javaString}).Parse(
+	`{{if .Package}}package {{.Package}};
+
+{{end}}import static io.gatling.javaapi.core.CoreDsl.*;
+import static io.gatling.javaapi.http.HttpDsl.*;
+
+import io.gatling.javaapi.core.*;
+import io.gatling.javaapi.http.*;
+import java.time.Duration;
+
+public class {{.Simulation}} extends Simulation {
+
+  Duration thinkTime = Duration.ofMillis(Long.getLong("thinkTime", {{.ThinkTime}}L));
+
+  HttpProtocolBuilder httpProtocol = http;
+
+  ScenarioBuilder scn = scenario({{q .Simulation}})
+{{- range .Requests}}
+    .exec(
+      http({{q .Name}})
+        .httpRequest({{q .Method}}, {{q .URL}})
+{{- range .Headers}}
+        .header({{q (index . 0)}}, {{q (index . 1)}})
+{{- end}}
+{{- if .Body}}
+        .body(StringBody({{q .Body}}))
+{{- end}}
+    )
+    .pause(thinkTime)
+{{- end}};
+
+  {
+    setUp(scn.injectOpen(atOnceUsers(1))).protocols(httpProtocol);
+  }
+}
+`))
+
+func (e *GatlingExporter) Export(w io.Writer, reqs []*Request) error {
+	requests := make([]gatlingRequest, 0, len(reqs))
+	for i, req := range reqs {
+		if req.URL == "" {
+			return fmt.Errorf("gatling: request %d has no URL", i)
+		}
+
+		r := gatlingRequest{
+			Name:   fmt.Sprintf("%d %s", i+1, req.Method),
+			Method: req.Method,
+			URL:    req.URL,
+			Body:   req.Body,
+		}
+		for _, k := range sortedKeys(req.Header) {
+			r.Headers = append(r.Headers, [2]string{k, req.Header[k]})
+		}
+		requests = append(requests, r)
+	}
+
+	simulation := e.Simulation
+	if simulation == "" {
+		simulation = "GcurlSimulation"
+	}
+	thinkTime := e.ThinkTime
+	if thinkTime == 0 {
+		thinkTime = time.Second
+	}
+
+	return gatlingTemplate.Execute(w, map[string]interface{}{
+		"Package":    e.Package,
+		"Simulation": simulation,
+		"ThinkTime":  thinkTime.Milliseconds(),
+		"Requests":   requests,
+	})
+}
+
+// javaString quotes s as a Java string literal.
+func javaString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == utf8.RuneError {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package gcurl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatlingExporter(t *testing.T) {
+	reqs := []*Request{
+		{
+			Method: "PUT",
+			URL:    "https://api.site.com/sloth/4",
+			Header: Header{"content-type": "application/json"},
+			Body:   "{\"name\":\"Sid\"}\n",
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	e := &GatlingExporter{Package: "perf", Simulation: "SlothSimulation"}
+	require.NoError(t, e.Export(buf, reqs))
+
+	out := buf.String()
+	require.Contains(t, out, "package perf;")
+	require.Contains(t, out, "public class SlothSimulation extends Simulation {")
+	require.Contains(t, out, `.httpRequest("PUT", "https://api.site.com/sloth/4")`)
+	require.Contains(t, out, `.header("content-type", "application/json")`)
+	require.Contains(t, out, `.body(StringBody("{\"name\":\"Sid\"}\n"))`)
+	require.Contains(t, out, `Long.getLong("thinkTime", 1000L)`)
+	require.Contains(t, out, ".pause(thinkTime);")
+}
+
+func TestJavaString(t *testing.T) {
+	require.Equal(t, `"a\\b\t\u0001é"`, javaString("a\\b\t\x01é"))
+}
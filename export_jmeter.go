@@ -0,0 +1,143 @@
+package gcurl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+// JMeterExporter renders requests as a JMeter JMX test plan with one
+// sampler per request, each followed by a think-time timer.
+type JMeterExporter struct {
+	TestName string
+	// ThinkTime is the default pause after each sampler. It can be
+	// overridden at run time with -Jthink_time=<ms>.
+	ThinkTime time.Duration
+}
+
+type jmeterSampler struct {
+	Name     string
+	Protocol string
+	Domain   string
+	Port     string
+	Path     string
+	Method   string
+	Body     string
+	Headers  [][2]string
+}
+
+var jmeterTemplate = template.Must(template.New("jmx").Funcs(template.FuncMap{"x": xmlEscape}).Parse(
+	`<?xml version="1.0" encoding="UTF-8"?>
+<jmeterTestPlan version="1.2" properties="5.0" jmeter="5.6.3">
+  <hashTree>
+    <TestPlan guiclass="TestPlanGui" testclass="TestPlan" testname="{{x .Name}}">
+      <elementProp name="TestPlan.user_defined_variables" elementType="Arguments" guiclass="ArgumentsPanel" testclass="Arguments">
+        <collectionProp name="Arguments.arguments"/>
+      </elementProp>
+    </TestPlan>
+    <hashTree>
+      <ThreadGroup guiclass="ThreadGroupGui" testclass="ThreadGroup" testname="Thread Group">
+        <stringProp name="ThreadGroup.num_threads">${__P(threads,1)}</stringProp>
+        <stringProp name="ThreadGroup.ramp_time">${__P(ramp_time,1)}</stringProp>
+        <elementProp name="ThreadGroup.main_controller" elementType="LoopController" guiclass="LoopControlPanel" testclass="LoopController">
+          <stringProp name="LoopController.loops">${__P(loops,1)}</stringProp>
+          <boolProp name="LoopController.continue_forever">false</boolProp>
+        </elementProp>
+      </ThreadGroup>
+      <hashTree>
+{{- range .Samplers}}
+        <HTTPSamplerProxy guiclass="HttpTestSampleGui" testclass="HTTPSamplerProxy" testname="{{x .Name}}">
+          <stringProp name="HTTPSampler.domain">{{x .Domain}}</stringProp>
+          <stringProp name="HTTPSampler.port">{{x .Port}}</stringProp>
+          <stringProp name="HTTPSampler.protocol">{{x .Protocol}}</stringProp>
+          <stringProp name="HTTPSampler.path">{{x .Path}}</stringProp>
+          <stringProp name="HTTPSampler.method">{{x .Method}}</stringProp>
+          <boolProp name="HTTPSampler.follow_redirects">true</boolProp>
+          <boolProp name="HTTPSampler.use_keepalive">true</boolProp>
+{{- if .Body}}
+          <boolProp name="HTTPSampler.postBodyRaw">true</boolProp>
+          <elementProp name="HTTPsampler.Arguments" elementType="Arguments">
+            <collectionProp name="Arguments.arguments">
+              <elementProp name="" elementType="HTTPArgument">
+                <boolProp name="HTTPArgument.always_encode">false</boolProp>
+                <stringProp name="Argument.value">{{x .Body}}</stringProp>
+                <stringProp name="Argument.metadata">=</stringProp>
+              </elementProp>
+            </collectionProp>
+          </elementProp>
+{{- end}}
+        </HTTPSamplerProxy>
+        <hashTree>
+{{- if .Headers}}
+          <HeaderManager guiclass="HeaderPanel" testclass="HeaderManager" testname="HTTP Header Manager">
+            <collectionProp name="HeaderManager.headers">
+{{- range .Headers}}
+              <elementProp name="" elementType="Header">
+                <stringProp name="Header.name">{{x (index . 0)}}</stringProp>
+                <stringProp name="Header.value">{{x (index . 1)}}</stringProp>
+              </elementProp>
+{{- end}}
+            </collectionProp>
+          </HeaderManager>
+          <hashTree/>
+{{- end}}
+          <ConstantTimer guiclass="ConstantTimerGui" testclass="ConstantTimer" testname="Think Time">
+            <stringProp name="ConstantTimer.delay">${__P(think_time,{{$.ThinkTime}})}</stringProp>
+          </ConstantTimer>
+          <hashTree/>
+        </hashTree>
+{{- end}}
+      </hashTree>
+    </hashTree>
+  </hashTree>
+</jmeterTestPlan>
+`))
+
+func (e *JMeterExporter) Export(w io.Writer, reqs []*Request) error {
+	samplers := make([]jmeterSampler, 0, len(reqs))
+	for _, req := range reqs {
+		u, err := url.Parse(req.URL)
+		if err != nil {
+			return fmt.Errorf("jmeter: %w", err)
+		}
+
+		s := jmeterSampler{
+			Name:     req.Method + " " + u.Path,
+			Protocol: u.Scheme,
+			Domain:   u.Hostname(),
+			Port:     u.Port(),
+			Path:     u.RequestURI(),
+			Method:   req.Method,
+			Body:     req.Body,
+		}
+		for _, k := range sortedKeys(req.Header) {
+			s.Headers = append(s.Headers, [2]string{k, req.Header[k]})
+		}
+		samplers = append(samplers, s)
+	}
+
+	name := e.TestName
+	if name == "" {
+		name = "gcurl"
+	}
+	thinkTime := e.ThinkTime
+	if thinkTime == 0 {
+		thinkTime = time.Second
+	}
+
+	return jmeterTemplate.Execute(w, map[string]interface{}{
+		"Name":      name,
+		"ThinkTime": thinkTime.Milliseconds(),
+		"Samplers":  samplers,
+	})
+}
+
+func xmlEscape(s string) string {
+	buf := &bytes.Buffer{}
+	_ = xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,44 @@
+package gcurl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJMeterExporter(t *testing.T) {
+	reqs := []*Request{
+		{
+			Method: "GET",
+			URL:    "https://api.site.com/sloths?limit=10",
+			Header: Header{"accept": "application/json"},
+		},
+		{
+			Method: "POST",
+			URL:    "http://api.site.com:8080/sloths",
+			Header: Header{"content-type": "application/json"},
+			Body:   `{"name":"<Sid>"}`,
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	e := &JMeterExporter{TestName: "sloths", ThinkTime: 250 * time.Millisecond}
+	require.NoError(t, e.Export(buf, reqs))
+
+	out := buf.String()
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), new(struct{})))
+	require.Contains(t, out, `testname="sloths"`)
+	require.Contains(t, out, `<stringProp name="HTTPSampler.path">/sloths?limit=10</stringProp>`)
+	require.Contains(t, out, `<stringProp name="HTTPSampler.port">8080</stringProp>`)
+	require.Contains(t, out, `<stringProp name="Header.name">accept</stringProp>`)
+	require.Contains(t, out, `{&#34;name&#34;:&#34;&lt;Sid&gt;&#34;}`)
+	require.Contains(t, out, `${__P(think_time,250)}`)
+}
+
+func TestJMeterExporterInvalidURL(t *testing.T) {
+	err := (&JMeterExporter{}).Export(&bytes.Buffer{}, []*Request{{Method: "GET", URL: "http://[::1"}})
+	require.Error(t, err)
+}
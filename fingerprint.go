@@ -0,0 +1,19 @@
+package gcurl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Fingerprint returns a stable hash identifying the request, suitable for
+// deduplication. It covers all of the canonical encoding of
+// MarshalBinary, including repeated headers, forms and files, except
+// Extensions.
+func (r *Request) Fingerprint() string {
+	c := *r
+	c.Extensions = nil
+	// Without extensions, the encoding can't fail.
+	data, _ := c.MarshalBinary()
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,36 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	a, err := Parse(`curl -H 'Accept: text/plain' -H 'X-Sloth: sid' https://api.site.com`)
+	require.NoError(t, err)
+	b, err := Parse(`curl -H 'X-Sloth: sid' -H 'Accept: text/plain' https://api.site.com`)
+	require.NoError(t, err)
+	c, err := Parse(`curl -H 'X-Sloth: manny' -H 'Accept: text/plain' https://api.site.com`)
+	require.NoError(t, err)
+
+	require.Len(t, a.Fingerprint(), 64)
+	require.Equal(t, a.Fingerprint(), b.Fingerprint())
+	require.NotEqual(t, a.Fingerprint(), c.Fingerprint())
+
+	for _, pair := range [][2]string{
+		{`curl -H 'X-A: 1' -H 'X-A: 2' https://api.site.com`, `curl -H 'X-A: 3' -H 'X-A: 2' https://api.site.com`},
+		{`curl -F name=sid https://api.site.com`, `curl -F name=manny https://api.site.com`},
+		{`curl --data-binary @a.bin https://api.site.com`, `curl --data-binary @b.bin https://api.site.com`},
+		{`curl -T a.bin https://api.site.com/`, `curl -T b.bin https://api.site.com/`},
+	} {
+		x, err := Parse(pair[0])
+		require.NoError(t, err)
+		y, err := Parse(pair[1])
+		require.NoError(t, err)
+		require.NotEqual(t, x.Fingerprint(), y.Fingerprint(), pair[0])
+	}
+
+	a.Extensions = map[string]interface{}{"note": "first seen in prod"}
+	require.Equal(t, b.Fingerprint(), a.Fingerprint())
+}
@@ -0,0 +1,317 @@
+package gcurl
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ContentTypeMultipart is the Content-Type of -F forms.
+const ContentTypeMultipart = "multipart/form-data"
+
+// FormField is a part of a multipart form given with -F or --form-string.
+type FormField struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	// File is the path of a file providing the content, for "@file" and
+	// "<file" parts.
+	File string `json:"file,omitempty"`
+	// Filename is sent with file uploads ("@file"). It is empty for parts
+	// whose value is read from a file ("<file").
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	// Headers are the extra part headers of ;headers= modifiers, as
+	// "Name: value" lines.
+	Headers []string `json:"headers,omitempty"`
+}
+
+// IsFile reports whether the part is a file upload.
+func (f FormField) IsFile() bool {
+	return f.Filename != ""
+}
+
+// parseFormField parses a -F argument: name=value, name=@file or
+// name=<file, optionally followed by ;type=, ;filename= and ;headers=
+// modifiers.
+// Values of --form-string (literal) are taken as is.
+func parseFormField(arg string, literal bool) (FormField, error) {
+	name, val, ok := strings.Cut(arg, "=")
+	if !ok || name == "" {
+		return FormField{}, fmt.Errorf("illegal form field %q", arg)
+	}
+	field := FormField{Name: name}
+	if literal {
+		field.Value = val
+		return field, nil
+	}
+
+	var upload bool
+	switch {
+	case strings.HasPrefix(val, "@"):
+		upload = true
+		val = val[1:]
+	case strings.HasPrefix(val, "<"):
+		val = val[1:]
+	default:
+		field.Value, val = cutFormWord(val)
+		return field, parseFormModifiers(&field, val)
+	}
+
+	field.File, val = cutFormWord(val)
+	if upload {
+		field.Filename = filepath.Base(field.File)
+	}
+	return field, parseFormModifiers(&field, val)
+}
+
+// cutFormWord splits s at the first ';', honoring double quotes in which
+// backslash escapes '"' and '\'.
+func cutFormWord(s string) (word, rest string) {
+	if !strings.HasPrefix(s, `"`) {
+		word, rest, _ = strings.Cut(s, ";")
+		if rest != "" || strings.HasSuffix(s, ";") {
+			rest = ";" + rest
+		}
+		return word, rest
+	}
+
+	b := &strings.Builder{}
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\'):
+			i++
+			b.WriteByte(s[i])
+		case c == '"':
+			return b.String(), s[i+1:]
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), ""
+}
+
+func parseFormModifiers(field *FormField, s string) error {
+	for s != "" {
+		if s[0] != ';' {
+			return fmt.Errorf("illegal form field modifiers %q", s)
+		}
+		key, val, _ := strings.Cut(s[1:], "=")
+		val, s = cutFormWord(val)
+		switch strings.TrimSpace(key) {
+		case "type":
+			field.ContentType = val
+		case "filename":
+			field.Filename = val
+		case "headers":
+			if strings.HasPrefix(val, "@") || strings.HasPrefix(val, "<") {
+				return fmt.Errorf("unsupported form field headers file %q", val)
+			}
+			if name, _, ok := strings.Cut(val, ":"); !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("illegal form field header %q", val)
+			}
+			field.Headers = append(field.Headers, val)
+		case "":
+		default:
+			return fmt.Errorf("unsupported form field modifier %q", key)
+		}
+	}
+	return nil
+}
+
+// curlArg returns the flag and argument giving the field in a curl
+// command.
+func (f FormField) curlArg() (flag, arg string) {
+	if f.File == "" && f.ContentType == "" && !f.IsFile() && len(f.Headers) == 0 {
+		if strings.ContainsAny(f.Value, `;"`) || strings.HasPrefix(f.Value, "@") || strings.HasPrefix(f.Value, "<") {
+			return "--form-string", f.Name + "=" + f.Value
+		}
+		return "-F", f.Name + "=" + f.Value
+	}
+
+	b := &strings.Builder{}
+	b.WriteString(f.Name + "=")
+	switch {
+	case f.File == "":
+		b.WriteString(quoteFormWord(f.Value))
+	case f.IsFile():
+		b.WriteString("@" + quoteFormWord(f.File))
+	default:
+		b.WriteString("<" + quoteFormWord(f.File))
+	}
+	if f.ContentType != "" {
+		b.WriteString(";type=" + quoteFormWord(f.ContentType))
+	}
+	if f.IsFile() && (f.File == "" || f.Filename != filepath.Base(f.File)) {
+		b.WriteString(";filename=" + quoteFormWord(f.Filename))
+	}
+	for _, h := range f.Headers {
+		b.WriteString(";headers=" + quoteFormWord(h))
+	}
+	return "-F", b.String()
+}
+
+// quoteFormWord is the reverse of cutFormWord.
+func quoteFormWord(s string) string {
+	if !strings.ContainsAny(s, `;"`) {
+		return s
+	}
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+	return `"` + s + `"`
+}
+
+var formQuoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func (f FormField) partHeader() textproto.MIMEHeader {
+	h := textproto.MIMEHeader{}
+	// Laid out like curl and multipart.CreateFormFile do.
+	disposition := `form-data; name="` + formQuoteEscaper.Replace(f.Name) + `"`
+	if f.IsFile() {
+		disposition += `; filename="` + formQuoteEscaper.Replace(f.Filename) + `"`
+	}
+	h.Set("Content-Disposition", disposition)
+	switch {
+	case f.ContentType != "":
+		h.Set("Content-Type", f.ContentType)
+	case f.IsFile():
+		h.Set("Content-Type", "application/octet-stream")
+	}
+	// Custom headers replace generated ones of the same name.
+	custom := textproto.MIMEHeader{}
+	for _, line := range f.Headers {
+		name, val, _ := strings.Cut(line, ":")
+		custom.Add(strings.TrimSpace(name), strings.TrimSpace(val))
+	}
+	for k, vals := range custom {
+		h[k] = vals
+	}
+	return h
+}
+
+// FormBoundary returns the multipart boundary given in the Content-Type
+// header, if any.
+func (r *Request) FormBoundary() string {
+	_, params, err := mime.ParseMediaType(r.Header[KeyContentType])
+	if err != nil {
+		return ""
+	}
+	return params["boundary"]
+}
+
+// SetFormBoundary fixes the multipart boundary in the Content-Type header
+// instead of a random one. Parts are always encoded in command order with
+// sorted headers, so the body is then stable, e.g. for golden files or
+// signatures over it.
+func (r *Request) SetFormBoundary(boundary string) error {
+	if err := multipart.NewWriter(io.Discard).SetBoundary(boundary); err != nil {
+		return fmt.Errorf("form boundary %q: %w", boundary, err)
+	}
+	mediaType, params, err := mime.ParseMediaType(r.Header[KeyContentType])
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		mediaType, params = ContentTypeMultipart, map[string]string{}
+	}
+	params["boundary"] = boundary
+	r.Header[KeyContentType] = mime.FormatMediaType(mediaType, params)
+	return nil
+}
+
+// newFormWriter returns a multipart writer using FormBoundary, or a
+// random boundary if the request doesn't set one.
+func (r *Request) newFormWriter(w io.Writer) (*multipart.Writer, error) {
+	mw := multipart.NewWriter(w)
+	if b := r.FormBoundary(); b != "" {
+		if err := mw.SetBoundary(b); err != nil {
+			return nil, fmt.Errorf("form boundary %q: %w", b, err)
+		}
+	}
+	return mw, nil
+}
+
+// formBody returns a reader streaming the multipart body of the form, the
+// body size and its Content-Type. Files are only checked here: they are
+// read as the body is consumed, so that large uploads never sit in
+// memory.
+func (r *Request) formBody() (body func() io.ReadCloser, size int64, contentType string, err error) {
+	for _, f := range r.Form {
+		if f.File == "" {
+			continue
+		}
+		if _, err := os.Stat(f.File); err != nil {
+			return nil, 0, "", fmt.Errorf("form field %q: %w", f.Name, err)
+		}
+	}
+	w, err := r.newFormWriter(io.Discard)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	form, boundary := append([]FormField(nil), r.Form...), w.Boundary()
+	size, _ = r.formSize()
+	body = func() io.ReadCloser {
+		pr, pw := io.Pipe()
+		return &formStream{form: form, boundary: boundary, pr: pr, pw: pw}
+	}
+	return body, size, w.FormDataContentType(), nil
+}
+
+// formStream is a multipart body written through a pipe by a goroutine
+// started on the first Read, so that a body which is never read holds
+// neither a goroutine nor an open file.
+type formStream struct {
+	form     []FormField
+	boundary string
+
+	once sync.Once
+	pr   *io.PipeReader
+	pw   *io.PipeWriter
+}
+
+func (s *formStream) Read(p []byte) (int, error) {
+	s.once.Do(func() {
+		go func() { s.pw.CloseWithError(writeForm(s.pw, s.form, s.boundary)) }()
+	})
+	return s.pr.Read(p)
+}
+
+// Close stops the writing goroutine, whose next write fails.
+func (s *formStream) Close() error {
+	return s.pr.Close()
+}
+
+func writeForm(dst io.Writer, form []FormField, boundary string) error {
+	w := multipart.NewWriter(dst)
+	if err := w.SetBoundary(boundary); err != nil {
+		return err
+	}
+	for _, f := range form {
+		part, err := w.CreatePart(f.partHeader())
+		if err != nil {
+			return err
+		}
+		if f.File == "" {
+			if _, err := io.WriteString(part, f.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := copyFile(part, f.File); err != nil {
+			return fmt.Errorf("form field %q: %w", f.Name, err)
+		}
+	}
+	return w.Close()
+}
+
+func copyFile(dst io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(dst, file)
+	return err
+}
@@ -0,0 +1,231 @@
+package gcurl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseForm(t *testing.T) {
+	var tests = []struct {
+		name  string
+		given string
+		form  []FormField
+	}{
+		{"value", `curl -F name=sid https://api.sloths.com`, []FormField{{Name: "name", Value: "sid"}}},
+		{"empty value", `curl -F name= https://api.sloths.com`, []FormField{{Name: "name"}}},
+		{"upload", `curl -F avatar=@/tmp/sid.png https://api.sloths.com`, []FormField{{Name: "avatar", File: "/tmp/sid.png", Filename: "sid.png"}}},
+		{"upload with modifiers", `curl --form 'avatar=@/tmp/sid.png;type=image/png;filename=me.png' https://api.sloths.com`,
+			[]FormField{{Name: "avatar", File: "/tmp/sid.png", Filename: "me.png", ContentType: "image/png"}}},
+		{"value from file", `curl -F 'bio=</tmp/bio.txt' https://api.sloths.com`, []FormField{{Name: "bio", File: "/tmp/bio.txt"}}},
+		{"typed value", `curl -F 'meta={"a":1};type=application/json' https://api.sloths.com`,
+			[]FormField{{Name: "meta", Value: `{"a":1}`, ContentType: ContentTypeJSON}}},
+		{"quoted value", `curl -F 'motto="slow; \"steady\"";type=text/plain' https://api.sloths.com`,
+			[]FormField{{Name: "motto", Value: `slow; "steady"`, ContentType: "text/plain"}}},
+		{"form string", `curl --form-string 'handle=@sid;x' https://api.sloths.com`, []FormField{{Name: "handle", Value: "@sid;x"}}},
+		{"several", `curl -F a=1 -F b=2 https://api.sloths.com`, []FormField{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}},
+		{"headers", `curl -F 'avatar=@/tmp/sid.png;headers="X-Sloth: sid";headers="X-Note: a;b"' https://api.sloths.com`,
+			[]FormField{{Name: "avatar", File: "/tmp/sid.png", Filename: "sid.png", Headers: []string{"X-Sloth: sid", "X-Note: a;b"}}}},
+		{"value with headers", `curl -F 'name=sid;headers=X-Sloth: sid' https://api.sloths.com`,
+			[]FormField{{Name: "name", Value: "sid", Headers: []string{"X-Sloth: sid"}}}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Parse(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.form, actual.Form)
+			require.Equal(t, "POST", actual.Method)
+			require.Equal(t, ContentTypeMultipart, actual.Header[KeyContentType])
+			require.Equal(t, BodyMultipart, actual.BodyKind)
+			require.Empty(t, actual.Body)
+
+			// Rendered commands parse back to the same form.
+			again, err := Parse(actual.curl())
+			require.NoError(t, err)
+			require.Equal(t, actual, again)
+		})
+	}
+}
+
+func TestParseFormErrors(t *testing.T) {
+	for _, given := range []string{
+		`curl -F novalue https://api.sloths.com`,
+		`curl -F '=sid' https://api.sloths.com`,
+		`curl -F 'a=@f;color=red' https://api.sloths.com`,
+		`curl -F 'a=@f;headers=@headers.txt' https://api.sloths.com`,
+		`curl -F 'a=1;headers=nocolon' https://api.sloths.com`,
+	} {
+		_, err := Parse(given)
+		require.Error(t, err, given)
+	}
+
+	_, err := Parse(`curl -F a=1 -d b=2 https://api.sloths.com`)
+	require.ErrorIs(t, err, ErrMethodConflict)
+}
+
+func TestEncodeForm(t *testing.T) {
+	dir := t.TempDir()
+	avatar := filepath.Join(dir, "sid.png")
+	bio := filepath.Join(dir, "bio.txt")
+	require.NoError(t, os.WriteFile(avatar, []byte("\x89PNG"), 0o644))
+	require.NoError(t, os.WriteFile(bio, []byte("hangs around"), 0o644))
+
+	req, err := Parse(`curl -F name=sid -F 'avatar=@` + avatar + `;type=image/png' -F 'bio=<` + bio + `' https://api.sloths.com`)
+	require.NoError(t, err)
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, hreq.ParseMultipartForm(1<<20))
+
+	require.Equal(t, []string{"sid"}, hreq.MultipartForm.Value["name"])
+	require.Equal(t, []string{"hangs around"}, hreq.MultipartForm.Value["bio"])
+	files := hreq.MultipartForm.File["avatar"]
+	require.Len(t, files, 1)
+	require.Equal(t, "sid.png", files[0].Filename)
+	require.Equal(t, "image/png", files[0].Header.Get("Content-Type"))
+	f, err := files[0].Open()
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, "\x89PNG", string(content))
+
+	req.Form[1].File = filepath.Join(dir, "missing.png")
+	_, err = req.ToHTTPRequest(context.Background())
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFormBoundary(t *testing.T) {
+	dir := t.TempDir()
+	bio := filepath.Join(dir, "bio.txt")
+	require.NoError(t, os.WriteFile(bio, []byte("hangs around"), 0o644))
+
+	req, err := Parse(`curl -F name=sid -F 'bio=@` + bio + `;type=text/plain' https://api.sloths.com`)
+	require.NoError(t, err)
+	require.Empty(t, req.FormBoundary())
+	require.NoError(t, req.SetFormBoundary("sloth-boundary"))
+	require.Equal(t, "multipart/form-data; boundary=sloth-boundary", req.Header[KeyContentType])
+	require.Equal(t, "sloth-boundary", req.FormBoundary())
+
+	expected := "--sloth-boundary\r\n" +
+		"Content-Disposition: form-data; name=\"name\"\r\n" +
+		"\r\n" +
+		"sid\r\n" +
+		"--sloth-boundary\r\n" +
+		"Content-Disposition: form-data; name=\"bio\"; filename=\"bio.txt\"\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"\r\n" +
+		"hangs around\r\n" +
+		"--sloth-boundary--\r\n"
+	for i := 0; i < 3; i++ {
+		hreq, err := req.ToHTTPRequest(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "multipart/form-data; boundary=sloth-boundary", hreq.Header.Get("Content-Type"))
+		body, err := io.ReadAll(hreq.Body)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(body))
+	}
+
+	// The boundary survives the round trip through a curl command.
+	again, err := Parse(req.curl())
+	require.NoError(t, err)
+	require.Equal(t, "sloth-boundary", again.FormBoundary())
+
+	require.Error(t, req.SetFormBoundary("bad boundary "))
+	req.Header[KeyContentType] = "multipart/form-data; boundary=\"bad boundary \""
+	_, err = req.ToHTTPRequest(context.Background())
+	require.ErrorContains(t, err, "form boundary")
+}
+
+func TestFormStreaming(t *testing.T) {
+	dir := t.TempDir()
+	video := filepath.Join(dir, "sloth.mp4")
+	content := bytes.Repeat([]byte("sloth"), 1<<20)
+	require.NoError(t, os.WriteFile(video, []byte("draft"), 0o644))
+
+	req, err := Parse(`curl -F title=nap -F 'video=@` + video + `;type=video/mp4' https://api.sloths.com`)
+	require.NoError(t, err)
+	require.NoError(t, req.SetFormBoundary("sloth-boundary"))
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+
+	// The file is only read as the body is consumed.
+	require.NoError(t, os.WriteFile(video, content, 0o644))
+	lazy, err := io.ReadAll(hreq.Body)
+	require.NoError(t, err)
+	require.True(t, bytes.Contains(lazy, content))
+
+	hreq, err = req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	_, size := req.WireSize()
+	require.Equal(t, size, hreq.ContentLength)
+
+	received := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		f, _, err := r.FormFile("video")
+		require.NoError(t, err)
+		defer f.Close()
+		b, err := io.ReadAll(f)
+		require.NoError(t, err)
+		received <- b
+	}))
+	defer srv.Close()
+
+	req.URL = srv.URL
+	resp, err := req.Execute(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, content, <-received)
+
+	// GetBody streams the same body again, e.g. for 307 redirects.
+	first, err := io.ReadAll(hreq.Body)
+	require.NoError(t, err)
+	again, err := hreq.GetBody()
+	require.NoError(t, err)
+	second, err := io.ReadAll(again)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.Len(t, first, int(hreq.ContentLength))
+
+	// Closing an unread body starts nothing.
+	unread, err := hreq.GetBody()
+	require.NoError(t, err)
+	require.NoError(t, unread.Close())
+
+	require.NoError(t, os.Remove(video))
+	body, err := hreq.GetBody()
+	require.NoError(t, err)
+	_, err = io.ReadAll(body)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFormPartHeaders(t *testing.T) {
+	req, err := Parse(`curl -F 'meta={};headers="X-Sloth: sid";headers="Content-Type: application/json"' https://api.sloths.com`)
+	require.NoError(t, err)
+	require.NoError(t, req.SetFormBoundary("sloth-boundary"))
+
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	body, err := io.ReadAll(hreq.Body)
+	require.NoError(t, err)
+	require.Equal(t, "--sloth-boundary\r\n"+
+		"Content-Disposition: form-data; name=\"meta\"\r\n"+
+		"Content-Type: application/json\r\n"+
+		"X-Sloth: sid\r\n"+
+		"\r\n"+
+		"{}\r\n"+
+		"--sloth-boundary--\r\n", string(body))
+	require.Equal(t, int64(len(body)), hreq.ContentLength)
+
+	clone := req.Clone()
+	clone.Form[0].Headers[0] = "X-Sloth: sam"
+	require.Equal(t, "X-Sloth: sid", req.Form[0].Headers[0])
+}
@@ -0,0 +1,128 @@
+// Package gcurltest provides helpers for testing HTTP handlers against
+// requests captured as curl commands, such as webhook deliveries.
+package gcurltest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hotrush/gcurl"
+)
+
+// HandlerTransport returns a RoundTripper serving requests with h in
+// process, without opening connections.
+func HandlerTransport(h http.Handler) http.RoundTripper {
+	return handlerTransport{h}
+}
+
+type handlerTransport struct {
+	h http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sreq := httptest.NewRequest(req.Method, req.URL.String(), req.Body)
+	sreq = sreq.WithContext(req.Context())
+	sreq.Header = req.Header.Clone()
+	if req.Host != "" {
+		sreq.Host = req.Host
+	}
+	sreq.ContentLength = req.ContentLength
+
+	rec := httptest.NewRecorder()
+	t.h.ServeHTTP(rec, sreq)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// Replay parses cmd and sends it to h, failing the test if the command
+// cannot be parsed or executed. Options such as gcurl.WithPreSendHook can
+// re-sign the delivery, e.g. to refresh a timestamped signature.
+func Replay(t testing.TB, h http.Handler, cmd string, opts ...gcurl.ExecOption) *gcurl.Response {
+	t.Helper()
+	opts = append(opts[:len(opts):len(opts)], gcurl.WithTransport(HandlerTransport(h)))
+	resp, err := gcurl.Do(context.Background(), cmd, opts...)
+	if err != nil {
+		t.Fatalf("replay %q: %v", cmd, err)
+	}
+	return resp
+}
+
+// AssertVerified replays the webhook delivery cmd against h and fails the
+// test unless h accepts it with a 2xx status.
+func AssertVerified(t testing.TB, h http.Handler, cmd string, opts ...gcurl.ExecOption) *gcurl.Response {
+	t.Helper()
+	resp := Replay(t, h, cmd, opts...)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		t.Errorf("delivery rejected with %s: %s", resp.Status, resp.Text())
+	}
+	return resp
+}
+
+// AssertRejected replays cmd against h and fails the test if h accepts
+// it, to check that tampered or stale deliveries are refused.
+func AssertRejected(t testing.TB, h http.Handler, cmd string, opts ...gcurl.ExecOption) *gcurl.Response {
+	t.Helper()
+	resp := Replay(t, h, cmd, opts...)
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		t.Errorf("delivery accepted with %s", resp.Status)
+	}
+	return resp
+}
+
+// Clock is a gcurl.Clock whose time only moves with Advance, so that
+// timeouts and expiry can be tested without sleeping. It is safe for
+// concurrent use.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers map[*clockTimer]struct{}
+}
+
+type clockTimer struct {
+	at time.Time
+	f  func()
+}
+
+// NewClock returns a Clock set to now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now, timers: map[*clockTimer]struct{}{}}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &clockTimer{at: c.now.Add(d), f: f}
+	c.timers[t] = struct{}{}
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		_, ok := c.timers[t]
+		delete(c.timers, t)
+		return ok
+	}
+}
+
+// Advance moves the clock forward by d and fires the timers now due, each
+// in its own goroutine.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for t := range c.timers {
+		if !t.at.After(c.now) {
+			delete(c.timers, t)
+			go t.f()
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package gcurltest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hotrush/gcurl"
+	"github.com/stretchr/testify/require"
+)
+
+// githubHandler verifies deliveries like GitHub webhooks do.
+func githubHandler(secret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Hub-Signature-256"))) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func TestAssertVerified(t *testing.T) {
+	h := githubHandler("It's a Secret to Everybody")
+
+	delivery := `curl -X POST -H 'Content-Type: text/plain' ` +
+		`-H 'X-Hub-Signature-256: sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17' ` +
+		`-d 'Hello, World!' https://hooks.site.com/github`
+	resp := AssertVerified(t, h, delivery)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	tampered := `curl -X POST -H 'Content-Type: text/plain' ` +
+		`-H 'X-Hub-Signature-256: sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17' ` +
+		`-d 'Hello, World?' https://hooks.site.com/github`
+	resp = AssertRejected(t, h, tampered)
+	require.Equal(t, "bad signature\n", resp.Text())
+}
+
+func TestReplayWithResigning(t *testing.T) {
+	// Slack-style signatures cover a timestamp that must be recent.
+	secret := []byte("8f742231b10e8888abcd99yyyzzz85a5")
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, err := strconv.ParseInt(r.Header.Get("X-Slack-Request-Timestamp"), 10, 64)
+		if err != nil || time.Since(time.Unix(ts, 0)) > 5*time.Minute {
+			http.Error(w, "stale", http.StatusUnauthorized)
+			return
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte("v0:" + strconv.FormatInt(ts, 10) + ":" + string(body)))
+		if r.Header.Get("X-Slack-Signature") != "v0="+hex.EncodeToString(mac.Sum(nil)) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+	})
+
+	delivery := `curl -H 'X-Slack-Request-Timestamp: 1531420618' ` +
+		`-H 'X-Slack-Signature: v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503' ` +
+		`-d 'token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J' https://hooks.site.com/slack`
+	resp := AssertRejected(t, h, delivery)
+	require.Equal(t, "stale\n", resp.Text())
+
+	resign := gcurl.WithPreSendHook(gcurl.PreSendHookFunc(func(req *http.Request) error {
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		data, _ := io.ReadAll(body)
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte("v0:" + ts + ":" + string(data)))
+		req.Header.Set("X-Slack-Request-Timestamp", ts)
+		req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+		return nil
+	}))
+	AssertVerified(t, h, delivery, resign)
+}
+
+func TestHandlerTransport(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Host", r.Host)
+		w.Header().Set("X-Path", r.URL.RequestURI())
+		w.Header().Set("X-Length", strconv.FormatInt(r.ContentLength, 10))
+		_, _ = io.Copy(w, r.Body)
+	})
+
+	resp := Replay(t, h, `curl -d 'a=1' 'https://api.site.com/sloths?page=2'`)
+	require.Equal(t, "api.site.com", resp.Header.Get("X-Host"))
+	require.Equal(t, "/sloths?page=2", resp.Header.Get("X-Path"))
+	require.Equal(t, "3", resp.Header.Get("X-Length"))
+	require.Equal(t, "a=1", resp.Text())
+}
+
+func TestClock(t *testing.T) {
+	clock := NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	hits := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Cache-Control", "max-age=60")
+	})
+	opts := []gcurl.ExecOption{gcurl.WithClock(clock), gcurl.WithResponseCache(gcurl.NewMemoryCache())}
+
+	Replay(t, h, "curl https://api.site.com/sloths", opts...)
+	clock.Advance(59 * time.Second)
+	Replay(t, h, "curl https://api.site.com/sloths", opts...)
+	require.Equal(t, 1, hits)
+	clock.Advance(2 * time.Second)
+	Replay(t, h, "curl https://api.site.com/sloths", opts...)
+	require.Equal(t, 2, hits)
+
+	fired := make(chan struct{})
+	stop := clock.AfterFunc(time.Minute, func() { close(fired) })
+	clock.Advance(59 * time.Second)
+	select {
+	case <-fired:
+		t.Fatal("timer fired early")
+	default:
+	}
+	clock.Advance(time.Second)
+	<-fired
+	require.False(t, stop())
+
+	stop = clock.AfterFunc(time.Second, func() { t.Error("stopped timer fired") })
+	require.True(t, stop())
+	clock.Advance(time.Hour)
+}
+
+func TestClockCronRunner(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewClock(start)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	c := gcurl.NewCatalog()
+	for _, e := range []*gcurl.CatalogEntry{
+		{Name: "minutely", Schedule: "@every 1m", Request: &gcurl.Request{Method: "GET", URL: "https://api.site.com/health", Header: gcurl.Header{}}},
+		{Name: "hourly", Schedule: "@hourly", Request: &gcurl.Request{Method: "GET", URL: "https://api.site.com/report", Header: gcurl.Header{}}},
+	} {
+		require.NoError(t, c.Add(e))
+	}
+
+	results := make(chan gcurl.CronResult, 10)
+	r := &gcurl.CronRunner{
+		Catalog:  c,
+		Options:  []gcurl.ExecOption{gcurl.WithClock(clock), gcurl.WithTransport(HandlerTransport(h))},
+		OnResult: func(res gcurl.CronResult) { results <- res },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	// Advance minute by minute until the first activation, which may be
+	// scheduled after the first advance.
+	var res gcurl.CronResult
+	require.Eventually(t, func() bool {
+		clock.Advance(time.Minute)
+		select {
+		case res = <-results:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "minutely", res.Name)
+	require.NoError(t, res.Err)
+	require.True(t, res.Time.After(start))
+	require.Zero(t, res.Duration)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	for len(results) > 0 {
+		require.Equal(t, "minutely", (<-results).Name)
+	}
+}
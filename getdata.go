@@ -0,0 +1,50 @@
+package gcurl
+
+import (
+	"errors"
+	"strings"
+)
+
+// DataOnGET selects how explicit GET and HEAD commands carrying data,
+// e.g. "curl -X GET -d q=sloth", are parsed. Such commands are usually a
+// mistake for a query string.
+type DataOnGET int
+
+const (
+	// DataOnGETKeep sends the data as a body, the method being chosen
+	// according to the MethodPrecedence.
+	DataOnGETKeep DataOnGET = iota
+	// DataOnGETError rejects the command with ErrDataOnGET.
+	DataOnGETError
+	// DataOnGETAsQuery keeps the method and moves the data to the query
+	// string, so the request can be replayed idempotently.
+	DataOnGETAsQuery
+)
+
+// ErrDataOnGET is returned for GET and HEAD commands with data when
+// parsing with DataOnGETError.
+var ErrDataOnGET = errors.New("data with GET or HEAD request")
+
+// WithDataOnGET sets how GET and HEAD commands with data are parsed.
+func WithDataOnGET(mode DataOnGET) ParseOption {
+	return func(p *Parser) { p.dataOnGET = mode }
+}
+
+// appendQuery appends an encoded query to rawURL.
+func appendQuery(rawURL, query string) string {
+	if query == "" {
+		return rawURL
+	}
+	frag := ""
+	if i := strings.IndexByte(rawURL, '#'); i >= 0 {
+		rawURL, frag = rawURL[:i], rawURL[i:]
+	}
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+		if strings.HasSuffix(rawURL, "?") || strings.HasSuffix(rawURL, "&") {
+			sep = ""
+		}
+	}
+	return rawURL + sep + query + frag
+}
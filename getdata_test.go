@@ -0,0 +1,77 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithDataOnGET(t *testing.T) {
+	var tests = []struct {
+		name   string
+		mode   DataOnGET
+		given  string
+		method string
+		url    string
+		body   string
+	}{
+		{"default", DataOnGETKeep, `curl -X GET -d 'q=sloth' https://api.site.com/search`, "GET", "https://api.site.com/search", "q=sloth"},
+		{"query", DataOnGETAsQuery, `curl -X GET -d 'q=sloth' -d 'page=2' https://api.site.com/search`, "GET", "https://api.site.com/search?q=sloth&page=2", ""},
+		{"query after method", DataOnGETAsQuery, `curl -d 'q=sloth' -X get 'https://api.site.com/search?lang=en#top'`, "GET", "https://api.site.com/search?lang=en&q=sloth#top", ""},
+		{"query head", DataOnGETAsQuery, `curl -I -d 'q=sloth' https://api.site.com/search`, "HEAD", "https://api.site.com/search?q=sloth", ""},
+		{"implicit POST untouched", DataOnGETAsQuery, `curl -d 'q=sloth' https://api.site.com/search`, "POST", "https://api.site.com/search", "q=sloth"},
+		{"other methods untouched", DataOnGETError, `curl -X PUT -d 'q=sloth' https://api.site.com/search`, "PUT", "https://api.site.com/search", "q=sloth"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewParser(WithDataOnGET(tt.mode)).Parse(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.method, actual.Method)
+			require.Equal(t, tt.url, actual.URL)
+			require.Equal(t, tt.body, actual.Body)
+			if tt.body == "" {
+				require.NotContains(t, actual.Header, KeyContentType)
+				require.Equal(t, BodyNone, actual.BodyKind)
+			}
+		})
+	}
+
+	_, err := NewParser(WithDataOnGET(DataOnGETError)).Parse(`curl -X GET -d 'q=sloth' https://api.site.com/search`)
+	require.ErrorIs(t, err, ErrDataOnGET)
+}
+
+func TestParseGet(t *testing.T) {
+	var tests = []struct {
+		name   string
+		given  string
+		method string
+		url    string
+	}{
+		{"data", `curl -G -d 'q=sloth' -d page=2 https://api.site.com/search`, "GET", "https://api.site.com/search?q=sloth&page=2"},
+		{"long flag after data", `curl -d 'q=sloth' --get 'https://api.site.com/search?lang=en'`, "GET", "https://api.site.com/search?lang=en&q=sloth"},
+		{"urlencode", `curl -G --data-urlencode 'q=two toed' https://api.site.com/search`, "GET", "https://api.site.com/search?q=two%20toed"},
+		{"head", `curl -G -I -d 'q=sloth' https://api.site.com/search`, "HEAD", "https://api.site.com/search?q=sloth"},
+		{"explicit method", `curl -G -X DELETE -d 'id=4' https://api.site.com/sloths`, "DELETE", "https://api.site.com/sloths?id=4"},
+		{"without data", `curl -G https://api.site.com/search`, "GET", "https://api.site.com/search"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, info, err := ParseWithInfo(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.method, actual.Method)
+			require.Equal(t, tt.url, actual.URL)
+			require.Empty(t, actual.Body)
+			require.NotContains(t, actual.Header, KeyContentType)
+			require.Empty(t, info.MethodConflict)
+		})
+	}
+
+	_, err := Parse(`curl -G --data-binary @q.txt https://api.site.com/search`)
+	require.ErrorIs(t, err, ErrNoFileReader)
+	_, err = Parse(`curl -G -F a=1 https://api.site.com/search`)
+	require.ErrorIs(t, err, ErrMethodConflict)
+}
@@ -2,13 +2,15 @@ module github.com/hotrush/gcurl
 
 go 1.21
 
-require github.com/mattn/go-shellwords v1.0.12
+require (
+	github.com/mattn/go-shellwords v1.0.12
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
@@ -0,0 +1,45 @@
+package gcurl
+
+import "strings"
+
+// Get returns the value of the header key, whatever its case.
+func (h Header) Get(key string) string {
+	v, _ := h.lookup(key)
+	return v
+}
+
+// Has reports whether the header key is set, whatever its case.
+func (h Header) Has(key string) bool {
+	_, ok := h.lookup(key)
+	return ok
+}
+
+// Set sets the header key under its lower-cased name, replacing any value
+// set with another case.
+func (h Header) Set(key, value string) {
+	h.Del(key)
+	h[strings.ToLower(key)] = value
+}
+
+// Del removes the header key, whatever its case.
+func (h Header) Del(key string) {
+	for k := range h {
+		if strings.EqualFold(k, key) {
+			delete(h, k)
+		}
+	}
+}
+
+// lookup finds key among the lower-cased keys set by the parser, then
+// among keys set by hand with another case.
+func (h Header) lookup(key string) (string, bool) {
+	if v, ok := h[strings.ToLower(key)]; ok {
+		return v, true
+	}
+	for k, v := range h {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
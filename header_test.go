@@ -0,0 +1,33 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeader(t *testing.T) {
+	req, err := Parse(`curl -H 'Content-Type: application/json' https://api.site.com`)
+	require.NoError(t, err)
+
+	h := req.Header
+	require.True(t, h.Has("Content-Type"))
+	require.Equal(t, "application/json", h.Get("CONTENT-TYPE"))
+	require.False(t, h.Has("Accept"))
+	require.Empty(t, h.Get("Accept"))
+
+	h.Set("X-Request-ID", "42")
+	require.Equal(t, "42", h["x-request-id"])
+	require.Equal(t, "42", h.Get("x-request-id"))
+
+	// Keys set by hand with another case are found and replaced.
+	h["X-Trace"] = "a"
+	require.Equal(t, "a", h.Get("x-trace"))
+	h.Set("x-TRACE", "b")
+	require.Equal(t, Header{"content-type": "application/json", "x-request-id": "42", "x-trace": "b"}, h)
+
+	h["X-Request-Id"] = "43"
+	h.Del("x-request-id")
+	require.False(t, h.Has("X-Request-ID"))
+	require.Equal(t, Header{"content-type": "application/json", "x-trace": "b"}, h)
+}
@@ -0,0 +1,78 @@
+package gcurl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrInvalidHeader is returned when a header name or value contains
+// control characters such as CR or LF, which could inject extra headers
+// once the request is sent.
+var ErrInvalidHeader = errors.New("invalid header")
+
+// WithControlCharStripping removes control characters from header names
+// and values instead of rejecting the command with ErrInvalidHeader.
+// Headers whose name is empty once stripped are dropped.
+func WithControlCharStripping() ParseOption {
+	return func(p *Parser) { p.stripControlChars = true }
+}
+
+// checkHeaders validates the headers of req, all the values of repeated
+// ones included, stripping control characters in place when strip is set.
+func checkHeaders(req *Request, strip bool) error {
+	h := req.Header
+	for k, vals := range req.MultiHeader {
+		invalid := hasControlChar(k)
+		for _, v := range vals {
+			invalid = invalid || hasControlChar(v)
+		}
+		if !invalid {
+			continue
+		}
+		if !strip {
+			return fmt.Errorf("header %q: %w: control character", k, ErrInvalidHeader)
+		}
+		delete(req.MultiHeader, k)
+		if name := stripControlChars(k); name != "" {
+			stripped := make([]string, len(vals))
+			for i, v := range vals {
+				stripped[i] = stripControlChars(v)
+			}
+			req.MultiHeader[http.CanonicalHeaderKey(name)] = stripped
+		}
+	}
+	for k, v := range h {
+		if !hasControlChar(k) && !hasControlChar(v) {
+			continue
+		}
+		if !strip {
+			return fmt.Errorf("header %q: %w: control character", k, ErrInvalidHeader)
+		}
+		delete(h, k)
+		if name := stripControlChars(k); name != "" {
+			h[name] = stripControlChars(v)
+		}
+	}
+	return nil
+}
+
+// hasControlChar reports whether s contains an ASCII control character
+// other than horizontal tab, which is allowed in header values.
+func hasControlChar(s string) bool {
+	return strings.IndexFunc(s, isControlChar) >= 0
+}
+
+func stripControlChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isControlChar(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func isControlChar(r rune) bool {
+	return r < ' ' && r != '\t' || r == 0x7f
+}
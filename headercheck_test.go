@@ -0,0 +1,44 @@
+package gcurl
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRejectsControlChars(t *testing.T) {
+	var tests = []struct {
+		name string
+		curl string
+	}{
+		{"CR in value", "curl -H 'X-Trace: 1\r2' https://api.site.com"},
+		{"CRLF injection", "curl -H 'X-Trace: 1\r\r\x00Evil: yes' https://api.site.com"},
+		{"control char in name", "curl -H 'X-\x01Trace: 1' https://api.site.com"},
+		{"user agent", "curl -A 'agent\r/1.0' https://api.site.com"},
+		{"cookie", "curl -b 'a=1\x7f' https://api.site.com"},
+		{"repeated header", "curl -H 'X-A: 1\r\nEvil: y' -H 'X-A: 2' https://api.site.com"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.curl)
+			require.ErrorIs(t, err, ErrInvalidHeader)
+			var perr *ParseError
+			require.ErrorAs(t, err, &perr)
+		})
+	}
+}
+
+func TestParseWithControlCharStripping(t *testing.T) {
+	p := NewParser(WithControlCharStripping())
+	actual, err := p.Parse("curl -H 'X-Trace: 1\r\x00Evil: yes' -H 'X-\x01Id:\t2' -H '\x02: 3' https://api.site.com")
+	require.NoError(t, err)
+	require.Equal(t, Header{"x-trace": "1Evil: yes", "x-id": "2"}, actual.Header)
+
+	actual, err = p.Parse("curl -H 'X-A: 1\r\nEvil: y' -H 'X-A: 2' https://api.site.com")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1Evil: y", "2"}, actual.HeaderValues("X-A"))
+	require.Equal(t, http.Header{"X-A": {"1Evil: y", "2"}}, actual.MultiHeader)
+}
@@ -0,0 +1,70 @@
+package gcurl
+
+import (
+	"path"
+	"strings"
+)
+
+// HeaderFilter selects headers by name. Patterns are matched
+// case-insensitively with path.Match syntax, e.g. "sec-fetch-*".
+type HeaderFilter struct {
+	// Allow, when not empty, keeps only the matching headers.
+	Allow []string
+	// Deny drops the matching headers. It takes precedence over Allow.
+	Deny []string
+}
+
+// Keep reports whether the header name passes the filter.
+func (f *HeaderFilter) Keep(name string) bool {
+	name = strings.ToLower(name)
+	if matchHeader(f.Deny, name) {
+		return false
+	}
+	return len(f.Allow) == 0 || matchHeader(f.Allow, name)
+}
+
+// Apply removes the headers not passing the filter from h.
+func (f *HeaderFilter) Apply(h Header) {
+	for k := range h {
+		if !f.Keep(k) {
+			delete(h, k)
+		}
+	}
+}
+
+func matchHeader(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithHeaderAllowList keeps only the headers matching patterns.
+func WithHeaderAllowList(patterns ...string) ParseOption {
+	return func(p *Parser) {
+		p.headerFilter.Allow = append(p.headerFilter.Allow, patterns...)
+	}
+}
+
+// WithHeaderDenyList drops the headers matching patterns.
+func WithHeaderDenyList(patterns ...string) ParseOption {
+	return func(p *Parser) {
+		p.headerFilter.Deny = append(p.headerFilter.Deny, patterns...)
+	}
+}
+
+// BrowserNoiseHeaders are the headers browsers add to "Copy as cURL"
+// commands that rarely matter when reproducing a request.
+var BrowserNoiseHeaders = []string{"sec-ch-ua*", "sec-fetch-*", "priority", "pragma"}
+
+// WithBrowserNoiseRemoval drops BrowserNoiseHeaders and ignores
+// --compressed, producing minimal commands from devtools exports.
+func WithBrowserNoiseRemoval() ParseOption {
+	deny := WithHeaderDenyList(BrowserNoiseHeaders...)
+	return func(p *Parser) {
+		deny(p)
+		p.ignoreCompressed = true
+	}
+}
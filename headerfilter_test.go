@@ -0,0 +1,77 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderFilter(t *testing.T) {
+	var tests = []struct {
+		name     string
+		filter   HeaderFilter
+		expected Header
+	}{
+		{
+			"deny",
+			HeaderFilter{Deny: []string{"Accept-Encoding", "sec-fetch-*"}},
+			Header{"accept": "*/*", "authorization": "Bearer t", "content-length": "3"},
+		},
+		{
+			"allow",
+			HeaderFilter{Allow: []string{"accept", "authorization"}},
+			Header{"accept": "*/*", "authorization": "Bearer t"},
+		},
+		{
+			"deny wins over allow",
+			HeaderFilter{Allow: []string{"a*"}, Deny: []string{"accept-*"}},
+			Header{"accept": "*/*", "authorization": "Bearer t"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			h := Header{
+				"accept":          "*/*",
+				"accept-encoding": "gzip",
+				"authorization":   "Bearer t",
+				"content-length":  "3",
+				"sec-fetch-mode":  "cors",
+				"sec-fetch-site":  "same-origin",
+			}
+			tt.filter.Apply(h)
+			require.Equal(t, tt.expected, h)
+		})
+	}
+}
+
+func TestParseWithHeaderFilter(t *testing.T) {
+	p := NewParser(WithHeaderDenyList("accept-encoding", "Sec-Fetch-*"))
+	actual, err := p.Parse(`curl -H 'Accept-Encoding: gzip' -H 'Sec-Fetch-Mode: cors' -H 'Accept: */*' https://api.site.com`)
+	require.NoError(t, err)
+	require.Equal(t, Header{"accept": "*/*"}, actual.Header)
+
+	p = NewParser(WithHeaderAllowList("authorization"))
+	actual, err = p.Parse(`curl -H 'Authorization: Bearer t' -H 'Accept: */*' https://api.site.com`)
+	require.NoError(t, err)
+	require.Equal(t, Header{"authorization": "Bearer t"}, actual.Header)
+}
+
+func TestParseWithBrowserNoiseRemoval(t *testing.T) {
+	given := `curl 'https://api.site.com/sloths' \
+  -H 'accept: application/json' \
+  -H 'pragma: no-cache' \
+  -H 'priority: u=1, i' \
+  -H 'sec-ch-ua: "Chromium";v="118"' \
+  -H 'sec-ch-ua-mobile: ?0' \
+  -H 'sec-ch-ua-platform: "macOS"' \
+  -H 'sec-fetch-dest: empty' \
+  -H 'sec-fetch-mode: cors' \
+  -H 'sec-fetch-site: same-origin' \
+  --compressed`
+
+	actual, err := NewParser(WithBrowserNoiseRemoval()).Parse(given)
+	require.NoError(t, err)
+	require.Equal(t, Header{"accept": "application/json"}, actual.Header)
+	require.False(t, actual.Compressed)
+}
@@ -0,0 +1,115 @@
+package gcurl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SecretProvider looks up secrets by name, e.g. in a vault or the
+// environment, so that replays don't send captured credentials.
+type SecretProvider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to the SecretProvider interface.
+type SecretProviderFunc func(ctx context.Context, name string) (string, error)
+
+func (f SecretProviderFunc) Secret(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+// WithSecretProvider resolves the secrets of header rules through p.
+func WithSecretProvider(p SecretProvider) ExecOption {
+	return func(c *execConfig) { c.secrets = p }
+}
+
+// HeaderRule mutates a header of the requests before they are sent, with
+// exactly one of Value, Secret or Drop.
+type HeaderRule struct {
+	// Host limits the rule to the requests for Host as captured, before
+	// rewrite rules apply, matched like RewriteRule.Host. Empty matches
+	// all requests.
+	Host string
+	// Name is the header name, or with Drop a pattern matched like
+	// HeaderFilter patterns, e.g. "x-debug-*".
+	Name string
+
+	// Value sets the header, replacing its values.
+	Value string
+	// Secret sets the header to the named secret of the SecretProvider.
+	Secret string
+	// Drop removes the header.
+	Drop bool
+}
+
+// SetHeader returns a rule setting the header name to value.
+func SetHeader(name, value string) HeaderRule {
+	return HeaderRule{Name: name, Value: value}
+}
+
+// SecretHeader returns a rule setting the header name to the named secret,
+// e.g. SecretHeader("Authorization", "staging/token").
+func SecretHeader(name, secret string) HeaderRule {
+	return HeaderRule{Name: name, Secret: secret}
+}
+
+// DropHeader returns a rule removing the headers matching pattern.
+func DropHeader(pattern string) HeaderRule {
+	return HeaderRule{Name: pattern, Drop: true}
+}
+
+// WithHeaderRules applies every matching rule, in order, to the requests
+// before they are sent, after rewrite rules and before authorization and
+// hooks. Given in BatchRunner.Options, they apply to the whole batch.
+// Cookies from a cookie jar are added afterwards and aren't affected.
+func WithHeaderRules(rules ...HeaderRule) ExecOption {
+	return func(c *execConfig) { c.headerRules = append(c.headerRules, rules...) }
+}
+
+// applyHeaderRules applies the rules matching captured, the URL of hreq
+// before rewriting.
+func (c *execConfig) applyHeaderRules(ctx context.Context, hreq *http.Request, captured *url.URL) error {
+	for _, rule := range c.headerRules {
+		if !matchHost(rule.Host, captured) {
+			continue
+		}
+		if err := c.applyHeaderRule(ctx, rule, hreq); err != nil {
+			return fmt.Errorf("header rule %s: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *execConfig) applyHeaderRule(ctx context.Context, rule HeaderRule, hreq *http.Request) error {
+	if rule.Drop {
+		for k := range hreq.Header {
+			if matchHeader([]string{rule.Name}, strings.ToLower(k)) {
+				delete(hreq.Header, k)
+			}
+		}
+		if matchHeader([]string{rule.Name}, "host") {
+			hreq.Host = ""
+		}
+		return nil
+	}
+
+	value := rule.Value
+	if rule.Secret != "" {
+		if c.secrets == nil {
+			return fmt.Errorf("no secret provider for %q", rule.Secret)
+		}
+		var err error
+		if value, err = c.secrets.Secret(ctx, rule.Secret); err != nil {
+			return err
+		}
+	}
+	if strings.EqualFold(rule.Name, "host") {
+		hreq.Host = value
+		return nil
+	}
+	hreq.Header.Set(rule.Name, value)
+	return nil
+}
@@ -0,0 +1,97 @@
+package gcurl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderRules(t *testing.T) {
+	var tests = []struct {
+		name     string
+		rules    []HeaderRule
+		expected http.Header
+		host     string
+	}{
+		{"set", []HeaderRule{SetHeader("x-replay", "true")}, http.Header{"X-Replay": {"true"}, "Authorization": {"Bearer captured"}, "Cookie": {"sid=1"}, "X-Debug-Id": {"7"}}, "api.prod.com"},
+		{"secret", []HeaderRule{SecretHeader("Authorization", "staging/token")}, http.Header{"Authorization": {"Bearer staging"}, "Cookie": {"sid=1"}, "X-Debug-Id": {"7"}}, "api.prod.com"},
+		{"drop", []HeaderRule{DropHeader("Cookie"), DropHeader("x-debug-*")}, http.Header{"Authorization": {"Bearer captured"}}, "api.prod.com"},
+		{"host header", []HeaderRule{SetHeader("Host", "api.staging.local")}, http.Header{"Authorization": {"Bearer captured"}, "Cookie": {"sid=1"}, "X-Debug-Id": {"7"}}, "api.staging.local"},
+		{"other host", []HeaderRule{{Host: "cdn.prod.com", Name: "X-Replay", Value: "true"}}, http.Header{"Authorization": {"Bearer captured"}, "Cookie": {"sid=1"}, "X-Debug-Id": {"7"}}, "api.prod.com"},
+		{"in order", []HeaderRule{SetHeader("X-Replay", "true"), DropHeader("x-*"), {Host: "API.prod.com", Name: "X-Replay", Value: "yes"}}, http.Header{"Authorization": {"Bearer captured"}, "Cookie": {"sid=1"}, "X-Replay": {"yes"}}, "api.prod.com"},
+	}
+
+	secrets := SecretProviderFunc(func(ctx context.Context, name string) (string, error) {
+		return map[string]string{"staging/token": "Bearer staging"}[name], nil
+	})
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := Parse(`curl -H 'Authorization: Bearer captured' -H 'Cookie: sid=1' -H 'X-Debug-Id: 7' https://api.prod.com/sloths`)
+			require.NoError(t, err)
+			cfg := newExecConfig([]ExecOption{WithHeaderRules(tt.rules...), WithSecretProvider(secrets)})
+			hreq, err := req.prepare(context.Background(), cfg)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, hreq.Header)
+			require.Equal(t, tt.host, hreq.Host)
+		})
+	}
+}
+
+func TestHeaderRuleSecretErrors(t *testing.T) {
+	req, err := Parse(`curl https://api.prod.com/sloths`)
+	require.NoError(t, err)
+
+	cfg := newExecConfig([]ExecOption{WithHeaderRules(SecretHeader("Authorization", "staging/token"))})
+	_, err = req.prepare(context.Background(), cfg)
+	require.EqualError(t, err, `header rule Authorization: no secret provider for "staging/token"`)
+
+	errVault := errors.New("vault sealed")
+	cfg = newExecConfig([]ExecOption{
+		WithHeaderRules(SecretHeader("Authorization", "staging/token")),
+		WithSecretProvider(SecretProviderFunc(func(ctx context.Context, name string) (string, error) {
+			return "", errVault
+		})),
+	})
+	_, err = req.prepare(context.Background(), cfg)
+	require.True(t, errors.Is(err, errVault))
+}
+
+func TestBatchHeaderRules(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Replay") + " " + r.Header.Get("Authorization") + " " + r.Header.Get("Cookie")))
+	}))
+	defer srv.Close()
+	staging := strings.TrimPrefix(srv.URL, "http://")
+
+	var reqs []*Request
+	for _, cmd := range []string{
+		`curl -H 'Authorization: Bearer captured' -b 'sid=1' https://api.prod.com/sloths`,
+		`curl -u admin:pa55 https://api.prod.com/sloths/4`,
+	} {
+		req, err := Parse(cmd)
+		require.NoError(t, err)
+		reqs = append(reqs, req)
+	}
+
+	b := &BatchRunner{Options: []ExecOption{
+		WithRewrite(RewriteRule{Host: "api.prod.com", ToHost: staging, ToScheme: "http"}),
+		WithHeaderRules(
+			SetHeader("X-Replay", "true"),
+			HeaderRule{Host: "api.prod.com", Name: "Authorization", Secret: "token"},
+			DropHeader("cookie"),
+		),
+		WithSecretProvider(SecretProviderFunc(func(ctx context.Context, name string) (string, error) {
+			return "Bearer staging", nil
+		})),
+	}}
+	for _, res := range b.Run(context.Background(), reqs) {
+		require.NoError(t, res.Err)
+		require.Equal(t, "true Bearer staging ", res.Response.Text())
+	}
+}
@@ -0,0 +1,168 @@
+package gcurl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ToHTTPRequest builds a net/http request ready to be sent with method,
+// URL, headers and body. Internationalized hosts are converted to
+// punycode, the path and query are encoded according to URLEncoding and
+// a Host header sets the request Host. BodyFile and UploadFile are read
+// here, while multipart forms are streamed as the body is sent.
+func (r *Request) ToHTTPRequest(ctx context.Context) (*http.Request, error) {
+	return r.toHTTPRequest(ctx, time.Now())
+}
+
+// toHTTPRequest is ToHTTPRequest with cookie file expiry checked at now.
+func (r *Request) toHTTPRequest(ctx context.Context, now time.Time) (*http.Request, error) {
+	var body io.Reader
+	var formType string
+	var formBody func() io.ReadCloser
+	var formSize int64
+	switch {
+	case r.Body != "":
+		body = strings.NewReader(r.Body)
+	case r.BodyFile != "":
+		b, err := readBodyFile(r.BodyFile)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	case r.UploadFile != "":
+		b, err := readBodyFile(r.UploadFile)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	case len(r.Form) > 0:
+		var err error
+		if formBody, formSize, formType, err = r.formBody(); err != nil {
+			return nil, err
+		}
+		body = formBody()
+	}
+
+	u, err := r.httpURL()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, u.Scheme+"://"+u.Host, body)
+	if err != nil {
+		return nil, err
+	}
+	req.URL = u
+	for k, v := range r.Header {
+		switch strings.ToLower(k) {
+		case "host":
+			req.Host = v
+		case "content-length", "transfer-encoding":
+			// Set from the body by setTransferLength.
+		default:
+			req.Header[http.CanonicalHeaderKey(k)] = r.HeaderValues(k)
+		}
+	}
+	if formType != "" {
+		// The Content-Type must carry the generated boundary.
+		req.Header.Set("Content-Type", formType)
+		req.ContentLength = formSize
+		req.GetBody = func() (io.ReadCloser, error) { return formBody(), nil }
+	}
+	if r.Auth != nil && req.Header.Get("Authorization") == "" {
+		if h, ok := r.Auth.header(); ok {
+			req.Header.Set("Authorization", h)
+		}
+	}
+	if r.CookieFile != "" {
+		cookies, err := r.fileCookies(now)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cookies {
+			req.AddCookie(&http.Cookie{Name: c.Name, Value: c.Value})
+		}
+	}
+	if err := r.setTransferLength(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// FromHTTPRequest converts a request into a Request. Incoming server
+// requests are supported: their URL is rebuilt from Host and TLS. The body
+// is read and replaced, so r can still be used afterwards. To render r as
+// a command string, see CurlCommand and CurlCommandRedacted.
+func FromHTTPRequest(r *http.Request) (*Request, error) {
+	u := *r.URL
+	if u.Host == "" {
+		u.Host = r.Host
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+
+	req := &Request{
+		Method: r.Method,
+		URL:    u.String(),
+		Header: Header{},
+	}
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+	for k, vals := range r.Header {
+		key := strings.ToLower(k)
+		if key == "content-length" || key == "host" {
+			continue
+		}
+		sep := ", "
+		if key == KeyCookie {
+			sep = "; "
+		}
+		req.Header[key] = strings.Join(vals, sep)
+	}
+	if r.Host != "" && r.Host != u.Host {
+		// A client request sent to a different Host than its URL.
+		req.Header.Set("host", r.Host)
+	}
+
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Body = string(body)
+	}
+	req.BodyKind = DetectBodyKind(req.Header[KeyContentType], req.Body)
+	return req, nil
+}
+
+// CurlCommand renders r as a shell-safe curl command, to reproduce it
+// exactly, secrets included. It's named so because FromHTTPRequest
+// already converts into a Request; use CurlCommandRedacted for logs. As
+// with FromHTTPRequest, the body is read and replaced.
+func CurlCommand(r *http.Request) (string, error) {
+	req, err := FromHTTPRequest(r)
+	if err != nil {
+		return "", err
+	}
+	return req.curl(), nil
+}
+
+// CurlCommandRedacted is CurlCommand with secrets redacted as by
+// Request.StringRedacted, so the command can be logged safely.
+func CurlCommandRedacted(r *http.Request) (string, error) {
+	req, err := FromHTTPRequest(r)
+	if err != nil {
+		return "", err
+	}
+	return req.StringRedacted(), nil
+}
@@ -0,0 +1,125 @@
+package gcurl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromHTTPRequest(t *testing.T) {
+	hreq := httptest.NewRequest(http.MethodPost, "/sloths?page=2", strings.NewReader(`{"name":"sid"}`))
+	hreq.Host = "api.site.com"
+	hreq.Header.Set("Content-Type", "application/json")
+	hreq.Header.Add("Accept", "text/html")
+	hreq.Header.Add("Accept", "application/json")
+	hreq.Header.Add("Cookie", "a=1")
+	hreq.Header.Add("Cookie", "b=2")
+	hreq.Header.Set("Content-Length", "14")
+
+	req, err := FromHTTPRequest(hreq)
+	require.NoError(t, err)
+	require.Equal(t, &Request{
+		Method: "POST",
+		URL:    "http://api.site.com/sloths?page=2",
+		Header: Header{
+			"accept":       "text/html, application/json",
+			"content-type": "application/json",
+			"cookie":       "a=1; b=2",
+		},
+		Body:     `{"name":"sid"}`,
+		BodyKind: BodyJSON,
+	}, req)
+
+	// The body can still be read by the handler.
+	body, err := io.ReadAll(hreq.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"sid"}`, string(body))
+
+	hreq = httptest.NewRequest(http.MethodGet, "https://api.site.com/", nil)
+	req, err = FromHTTPRequest(hreq)
+	require.NoError(t, err)
+	require.Equal(t, "https://api.site.com/", req.URL)
+	require.Empty(t, req.Body)
+}
+
+func TestFromHTTPRequestRoundTrip(t *testing.T) {
+	orig, err := Parse(`curl -X PUT -H 'X-Sloth: sid' -H 'Content-Type: application/json' -d '{"a":1}' 'https://api.site.com/sloths/4?x=y'`)
+	require.NoError(t, err)
+
+	hreq, err := orig.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	req, err := FromHTTPRequest(hreq)
+	require.NoError(t, err)
+	require.Equal(t, orig, req)
+}
+
+func TestToHTTPRequest(t *testing.T) {
+	req, err := NewParser(WithContentLengthOverride()).Parse(`curl -X PATCH -H 'Host: internal.site.com' -H 'Content-Length: 99' -H 'X-Sloth: sid' -b 'session=abc; theme=dark' -d 'a=1' https://10.0.0.1/sloths/4`)
+	require.NoError(t, err)
+
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "PATCH", hreq.Method)
+	require.Equal(t, "https://10.0.0.1/sloths/4", hreq.URL.String())
+	require.Equal(t, "internal.site.com", hreq.Host)
+	require.Empty(t, hreq.Header.Get("Host"))
+	require.Empty(t, hreq.Header.Get("Content-Length"))
+	require.Equal(t, int64(3), hreq.ContentLength)
+	require.Equal(t, "sid", hreq.Header.Get("X-Sloth"))
+	require.Equal(t, ContentTypeForm, hreq.Header.Get("Content-Type"))
+
+	cookie, err := hreq.Cookie("theme")
+	require.NoError(t, err)
+	require.Equal(t, "dark", cookie.Value)
+
+	body, err := io.ReadAll(hreq.Body)
+	require.NoError(t, err)
+	require.Equal(t, "a=1", string(body))
+
+	// The body can be replayed on redirects and retries.
+	require.NotNil(t, hreq.GetBody)
+
+	_, err = (&Request{Method: "GET", URL: "://bad"}).ToHTTPRequest(context.Background())
+	require.Error(t, err)
+}
+
+func TestCurlCommand(t *testing.T) {
+	hreq, err := http.NewRequest(http.MethodPut, "https://10.0.0.1/sloths/4?x=y", strings.NewReader(`it's {"a":1}`))
+	require.NoError(t, err)
+	hreq.Host = "api.site.com"
+	hreq.Header.Set("X-Sloth", "sid")
+
+	cmd, err := CurlCommand(hreq)
+	require.NoError(t, err)
+	require.Equal(t, `curl -X PUT -H 'host: api.site.com' -H 'x-sloth: sid' --data-raw 'it'\''s {"a":1}' 'https://10.0.0.1/sloths/4?x=y'`, cmd)
+
+	req, err := Parse(cmd)
+	require.NoError(t, err)
+	again, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "api.site.com", again.Host)
+	require.Equal(t, hreq.URL.String(), again.URL.String())
+
+	body, err := io.ReadAll(hreq.Body)
+	require.NoError(t, err)
+	require.Equal(t, `it's {"a":1}`, string(body))
+}
+
+func TestCurlCommandRedacted(t *testing.T) {
+	hreq, err := http.NewRequest(http.MethodGet, "https://api.site.com/sloths", nil)
+	require.NoError(t, err)
+	hreq.Header.Set("Authorization", "Bearer s3cr3t")
+
+	cmd, err := CurlCommandRedacted(hreq)
+	require.NoError(t, err)
+	require.Equal(t, `curl -H 'authorization: Bearer REDACTED' https://api.site.com/sloths`, cmd)
+
+	cmd, err = CurlCommand(hreq)
+	require.NoError(t, err)
+	require.Contains(t, cmd, "s3cr3t")
+}
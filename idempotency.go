@@ -0,0 +1,36 @@
+package gcurl
+
+import (
+	"net/http"
+	"strings"
+)
+
+// IsSafe reports whether the request method is safe per RFC 9110, i.e.
+// read-only: GET, HEAD, OPTIONS or TRACE.
+func (r *Request) IsSafe() bool {
+	switch strings.ToUpper(r.Method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// IsIdempotent reports whether repeating the request has the same effect
+// as sending it once, so it can be retried after a failure. That is the
+// case for safe methods, PUT and DELETE, and for any request carrying an
+// Idempotency-Key header.
+func (r *Request) IsIdempotent() bool {
+	if r.IsSafe() {
+		return true
+	}
+	switch strings.ToUpper(r.Method) {
+	case http.MethodPut, http.MethodDelete:
+		return true
+	}
+	for k, v := range r.Header {
+		if (strings.EqualFold(k, "idempotency-key") || strings.EqualFold(k, "x-idempotency-key")) && v != "" {
+			return true
+		}
+	}
+	return false
+}
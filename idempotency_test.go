@@ -0,0 +1,32 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIdempotency(t *testing.T) {
+	var tests = []struct {
+		curl       string
+		safe       bool
+		idempotent bool
+	}{
+		{`curl https://api.site.com`, true, true},
+		{`curl -I https://api.site.com`, true, true},
+		{`curl -X options https://api.site.com`, true, true},
+		{`curl -X PUT -d 'a=1' https://api.site.com`, false, true},
+		{`curl -X DELETE https://api.site.com`, false, true},
+		{`curl -d 'a=1' https://api.site.com`, false, false},
+		{`curl -X PATCH -d 'a=1' https://api.site.com`, false, false},
+		{`curl -H 'Idempotency-Key: 8e03978e' -d 'a=1' https://api.site.com`, false, true},
+		{`curl -H 'Idempotency-Key:' -d 'a=1' https://api.site.com`, false, false},
+	}
+
+	for _, tt := range tests {
+		req, err := Parse(tt.curl)
+		require.NoError(t, err)
+		require.Equal(t, tt.safe, req.IsSafe(), tt.curl)
+		require.Equal(t, tt.idempotent, req.IsIdempotent(), tt.curl)
+	}
+}
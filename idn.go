@@ -0,0 +1,257 @@
+package gcurl
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// errPunycode is returned for labels that are not valid punycode.
+var errPunycode = errors.New("invalid punycode")
+
+// ToASCIIHost converts the internationalized labels of host, which may
+// carry a port, to punycode ("bücher.example" to
+// "xn--bcher-kva.example"). Labels are lower-cased but not otherwise
+// normalized, so hosts should be given in NFC.
+func ToASCIIHost(host string) (string, error) {
+	name, port := splitHostPort(host)
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		if !utf8.ValidString(label) {
+			return "", errors.New("invalid UTF-8 in host " + host)
+		}
+		labels[i] = "xn--" + punycodeEncode([]rune(strings.ToLower(label)))
+	}
+	return strings.Join(labels, ".") + port, nil
+}
+
+// ToUnicodeHost is the reverse of ToASCIIHost, for display. Labels that
+// aren't valid punycode are kept as is.
+func ToUnicodeHost(host string) string {
+	name, port := splitHostPort(host)
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if len(label) < 4 || !strings.EqualFold(label[:4], "xn--") {
+			continue
+		}
+		if decoded, err := punycodeDecode(strings.ToLower(label[4:])); err == nil {
+			labels[i] = decoded
+		}
+	}
+	return strings.Join(labels, ".") + port
+}
+
+// DisplayURL returns the request URL with its host in Unicode.
+func (r *Request) DisplayURL() string {
+	prefix, host, rest, ok := splitURLHost(r.URL)
+	if !ok {
+		return r.URL
+	}
+	return prefix + ToUnicodeHost(host) + rest
+}
+
+// asciiURL returns rawURL with its host converted by ToASCIIHost.
+func asciiURL(rawURL string) (string, error) {
+	prefix, host, rest, ok := splitURLHost(rawURL)
+	if !ok || isASCII(host) {
+		return rawURL, nil
+	}
+	if h, err := url.PathUnescape(host); err == nil {
+		host = h
+	}
+	host, err := ToASCIIHost(host)
+	if err != nil {
+		return "", err
+	}
+	return prefix + host + rest, nil
+}
+
+// splitURLHost splits rawURL around its host and port. prefix holds the
+// scheme and userinfo.
+func splitURLHost(rawURL string) (prefix, host, rest string, ok bool) {
+	_, after, found := strings.Cut(rawURL, "://")
+	if !found {
+		return "", "", "", false
+	}
+	if _, _, r, hasInfo := splitUserinfo(rawURL); hasInfo {
+		after = r
+	}
+	end := len(after)
+	if i := strings.IndexAny(after, "/?#"); i >= 0 {
+		end = i
+	}
+	return rawURL[:len(rawURL)-len(after)], after[:end], after[end:], true
+}
+
+func splitHostPort(host string) (name, port string) {
+	if strings.HasPrefix(host, "[") {
+		return host, ""
+	}
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		return h, ":" + p
+	}
+	return host, ""
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Punycode parameters of RFC 3492.
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+func punycodeEncode(input []rune) string {
+	var out []byte
+	for _, c := range input {
+		if c < utf8.RuneSelf {
+			out = append(out, byte(c))
+		}
+	}
+	b := len(out)
+	h := b
+	if b > 0 {
+		out = append(out, '-')
+	}
+
+	n, delta, bias := rune(punyInitialN), 0, punyInitialBias
+	for h < len(input) {
+		m := rune(utf8.MaxRune + 1)
+		for _, c := range input {
+			if c >= n && c < m {
+				m = c
+			}
+		}
+		delta += int(m-n) * (h + 1)
+		n = m
+		for _, c := range input {
+			if c < n {
+				delta++
+			}
+			if c != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := punyThreshold(k, bias)
+				if q < t {
+					break
+				}
+				out = append(out, punyDigit(t+(q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out = append(out, punyDigit(q))
+			bias = punyAdapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+	return string(out)
+}
+
+func punycodeDecode(s string) (string, error) {
+	var out []rune
+	pos := 0
+	if b := strings.LastIndexByte(s, '-'); b >= 0 {
+		for i := 0; i < b; i++ {
+			if s[i] >= utf8.RuneSelf {
+				return "", errPunycode
+			}
+			out = append(out, rune(s[i]))
+		}
+		pos = b + 1
+	}
+
+	n, i, bias := rune(punyInitialN), 0, punyInitialBias
+	for pos < len(s) {
+		oldi, w := i, 1
+		for k := punyBase; ; k += punyBase {
+			if pos >= len(s) {
+				return "", errPunycode
+			}
+			digit, ok := punyDigitValue(s[pos])
+			pos++
+			if !ok || digit > (1<<30-i)/w {
+				return "", errPunycode
+			}
+			i += digit * w
+			t := punyThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			w *= punyBase - t
+		}
+		bias = punyAdapt(i-oldi, len(out)+1, oldi == 0)
+		n += rune(i / (len(out) + 1))
+		i %= len(out) + 1
+		if n > utf8.MaxRune || n < punyInitialN {
+			return "", errPunycode
+		}
+		out = append(out[:i], append([]rune{n}, out[i:]...)...)
+		i++
+	}
+	return string(out), nil
+}
+
+func punyThreshold(k, bias int) int {
+	switch {
+	case k <= bias:
+		return punyTMin
+	case k >= bias+punyTMax:
+		return punyTMax
+	}
+	return k - bias
+}
+
+func punyAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+	k := 0
+	for delta > (punyBase-punyTMin)*punyTMax/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+func punyDigitValue(c byte) (int, bool) {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A'), true
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 26, true
+	}
+	return 0, false
+}
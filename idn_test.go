@@ -0,0 +1,51 @@
+package gcurl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToASCIIHost(t *testing.T) {
+	var tests = []struct {
+		unicode string
+		ascii   string
+	}{
+		{"bücher.example", "xn--bcher-kva.example"},
+		{"München.de:8443", "xn--mnchen-3ya.de:8443"},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+		{"пример.рф", "xn--e1afmkfd.xn--p1ai"},
+		{"api.site.com", "api.site.com"},
+		{"[2001:db8::1]:443", "[2001:db8::1]:443"},
+	}
+
+	for _, tt := range tests {
+		ascii, err := ToASCIIHost(tt.unicode)
+		require.NoError(t, err)
+		require.Equal(t, tt.ascii, ascii, tt.unicode)
+	}
+	require.Equal(t, "bücher.example", ToUnicodeHost("xn--bcher-kva.example"))
+	require.Equal(t, "例え.テスト:80", ToUnicodeHost("XN--r8jz45g.xn--zckzah:80"))
+	require.Equal(t, "xn--!!.example", ToUnicodeHost("xn--!!.example"))
+}
+
+func TestIDNRequest(t *testing.T) {
+	req, err := Parse(`curl 'https://sid:pa55@bücher.example:8443/straße?q=ü'`)
+	require.NoError(t, err)
+
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "xn--bcher-kva.example:8443", hreq.URL.Host)
+	require.Equal(t, "sid", hreq.URL.User.Username())
+
+	wire, err := req.DumpWire()
+	require.NoError(t, err)
+	require.Contains(t, string(wire), "Host: xn--bcher-kva.example:8443\r\n")
+	headers, body := req.WireSize()
+	require.Equal(t, int64(len(wire)), headers+body)
+	require.Equal(t, "xn--bcher-kva.example:8443", req.Plan().Header.Get("Host"))
+
+	req.URL = hreq.URL.String()
+	require.Equal(t, "https://sid:pa55@bücher.example:8443/stra%C3%9Fe?q=ü", req.DisplayURL())
+}
@@ -0,0 +1,57 @@
+package gcurl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// BurpImporter reads Burp Suite "Save items" XML exports.
+type BurpImporter struct{}
+
+type burpItems struct {
+	Items []burpItem `xml:"item"`
+}
+
+type burpItem struct {
+	URL      string `xml:"url"`
+	Protocol string `xml:"protocol"`
+	Request  struct {
+		Base64 bool   `xml:"base64,attr"`
+		Data   string `xml:",chardata"`
+	} `xml:"request"`
+}
+
+func (BurpImporter) Import(r io.Reader) ([]*Request, error) {
+	var items burpItems
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	if err := dec.Decode(&items); err != nil {
+		return nil, fmt.Errorf("burp: %w", err)
+	}
+
+	reqs := make([]*Request, 0, len(items.Items))
+	for i, item := range items.Items {
+		raw := []byte(item.Request.Data)
+		if item.Request.Base64 {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(item.Request.Data))
+			if err != nil {
+				return nil, fmt.Errorf("burp: item %d: %w", i+1, err)
+			}
+			raw = decoded
+		}
+
+		req, err := parseRawHTTP(bytes.NewReader(raw), item.Protocol)
+		if err != nil {
+			return nil, fmt.Errorf("burp: item %d: %w", i+1, err)
+		}
+		if item.URL != "" {
+			req.URL = strings.TrimSpace(item.URL)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
@@ -0,0 +1,65 @@
+package gcurl
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurpImporter(t *testing.T) {
+	raw := "POST /sloths HTTP/1.1\r\n" +
+		"Host: api.site.com\r\n" +
+		"Content-Type: application/json\r\n" +
+		"Content-Length: 14\r\n" +
+		"\r\n" +
+		`{"name":"sid"}`
+
+	given := `<?xml version="1.0"?>
+<!DOCTYPE items [
+<!ELEMENT items (item*)>
+]>
+<items burpVersion="2023.10" exportTime="Mon Oct 16 10:00:00 UTC 2023">
+  <item>
+    <url><![CDATA[https://api.site.com/sloths]]></url>
+    <host ip="10.0.0.1">api.site.com</host>
+    <port>443</port>
+    <protocol>https</protocol>
+    <method><![CDATA[POST]]></method>
+    <request base64="true"><![CDATA[` + base64.StdEncoding.EncodeToString([]byte(raw)) + `]]></request>
+    <status>201</status>
+  </item>
+  <item>
+    <url><![CDATA[http://api.site.com/health]]></url>
+    <protocol>http</protocol>
+    <request base64="false"><![CDATA[GET /health HTTP/1.1
+Host: api.site.com
+
+]]></request>
+  </item>
+</items>`
+
+	actual, err := BurpImporter{}.Import(strings.NewReader(given))
+	require.NoError(t, err)
+	require.Equal(t, []*Request{
+		{
+			Method:   "POST",
+			URL:      "https://api.site.com/sloths",
+			Header:   Header{"content-type": "application/json"},
+			Body:     `{"name":"sid"}`,
+			BodyKind: BodyJSON,
+		},
+		{
+			Method: "GET",
+			URL:    "http://api.site.com/health",
+			Header: Header{},
+		},
+	}, actual)
+}
+
+func TestBurpImporterInvalid(t *testing.T) {
+	given := `<items><item><request base64="true">!!!</request></item></items>`
+	_, err := BurpImporter{}.Import(strings.NewReader(given))
+	require.ErrorContains(t, err, "burp: item 1:")
+}
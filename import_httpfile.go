@@ -0,0 +1,140 @@
+package gcurl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// HTTPFileImporter reads .http/.rest files as used by the VS Code REST
+// Client and JetBrains HTTP Client. Requests are separated by lines
+// starting with ###, and file-level @name = value variables are
+// substituted into {{name}} placeholders. Request names given with
+// "### name" or "# @name name" are kept in the "httpfile.name" extension.
+type HTTPFileImporter struct{}
+
+var (
+	httpFileVariable = regexp.MustCompile(`^@([\w.-]+)\s*=\s*(.*)$`)
+	httpFilePlace    = regexp.MustCompile(`{{\s*([\w.-]+)\s*}}`)
+)
+
+func (HTTPFileImporter) Import(r io.Reader) ([]*Request, error) {
+	vars := map[string]string{}
+	var reqs []*Request
+	var block []string
+	var title string
+	blockLine := 1
+
+	flush := func() error {
+		req, err := parseHTTPFileBlock(block, title, vars)
+		if err != nil {
+			return fmt.Errorf("http file: line %d: %w", blockLine, err)
+		}
+		if req != nil {
+			reqs = append(reqs, req)
+		}
+		block = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(text, "###") {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			title = strings.TrimSpace(strings.TrimLeft(text, "#"))
+			blockLine = line + 1
+			continue
+		}
+		if m := httpFileVariable.FindStringSubmatch(text); m != nil && len(block) == 0 {
+			vars[m[1]] = strings.TrimSpace(m[2])
+			continue
+		}
+		block = append(block, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+func parseHTTPFileBlock(lines []string, name string, vars map[string]string) (*Request, error) {
+	expand := func(s string) string { return expandPlaceholders(s, vars) }
+
+	// Skip leading blank lines and comments.
+	i := 0
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		comment := strings.TrimLeft(trimmed, "#/")
+		if comment == trimmed && trimmed != "" {
+			break
+		}
+		if n, ok := strings.CutPrefix(strings.TrimSpace(comment), "@name "); ok {
+			name = strings.TrimSpace(n)
+		}
+	}
+	if i == len(lines) {
+		return nil, nil
+	}
+
+	req := &Request{
+		Method: http.MethodGet,
+		Header: Header{},
+	}
+	if name != "" {
+		req.SetExtension("httpfile.name", name)
+	}
+
+	fields := strings.Fields(expand(lines[i]))
+	switch {
+	case len(fields) == 1:
+		req.URL = fields[0]
+	case len(fields) >= 2:
+		req.Method = strings.ToUpper(fields[0])
+		req.URL = fields[1]
+	}
+	if !isURL(req.URL) {
+		return nil, fmt.Errorf("invalid request line %q", lines[i])
+	}
+
+	for i++; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			break
+		}
+		key, val, ok := strings.Cut(expand(lines[i]), ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed header %q", lines[i])
+		}
+		req.Header[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(val)
+	}
+
+	if i < len(lines) {
+		body := strings.Trim(strings.Join(lines[i+1:], "\n"), "\n")
+		req.Body = expand(body)
+		req.BodyKind = DetectBodyKind(req.Header[KeyContentType], req.Body)
+	}
+	return req, nil
+}
+
+// expandPlaceholders substitutes {{name}} placeholders with vars, leaving
+// unknown ones untouched.
+func expandPlaceholders(s string, vars map[string]string) string {
+	return httpFilePlace.ReplaceAllStringFunc(s, func(m string) string {
+		name := httpFilePlace.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
@@ -0,0 +1,59 @@
+package gcurl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPFileImporter(t *testing.T) {
+	given := `@host = https://api.site.com
+@token = s3cr3t
+
+### list sloths
+GET {{host}}/sloths HTTP/1.1
+Accept: application/json
+Authorization: Bearer {{token}}
+
+###
+# @name create
+POST {{host}}/sloths
+Content-Type: application/json
+
+{
+  "name": "Sid"
+}
+
+###
+{{host}}/health
+`
+	actual, err := HTTPFileImporter{}.Import(strings.NewReader(given))
+	require.NoError(t, err)
+	require.Equal(t, []*Request{
+		{
+			Method:     "GET",
+			URL:        "https://api.site.com/sloths",
+			Header:     Header{"accept": "application/json", "authorization": "Bearer s3cr3t"},
+			Extensions: map[string]interface{}{"httpfile.name": "list sloths"},
+		},
+		{
+			Method:     "POST",
+			URL:        "https://api.site.com/sloths",
+			Header:     Header{"content-type": "application/json"},
+			Body:       "{\n  \"name\": \"Sid\"\n}",
+			BodyKind:   BodyJSON,
+			Extensions: map[string]interface{}{"httpfile.name": "create"},
+		},
+		{
+			Method: "GET",
+			URL:    "https://api.site.com/health",
+			Header: Header{},
+		},
+	}, actual)
+}
+
+func TestHTTPFileImporterInvalid(t *testing.T) {
+	_, err := HTTPFileImporter{}.Import(strings.NewReader("GET not-a-url\n"))
+	require.Error(t, err)
+}
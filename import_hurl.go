@@ -0,0 +1,175 @@
+package gcurl
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// HurlImporter reads Hurl files. Response sections (HTTP status lines,
+// captures and asserts) are skipped; only the request parts are kept.
+type HurlImporter struct{}
+
+var (
+	hurlRequestLine  = regexp.MustCompile(`^([A-Z]+)\s+(\S+)\s*$`)
+	hurlResponseLine = regexp.MustCompile(`^HTTP(/[0-9.]+)?(\s+([0-9]{3}|\*))?$`)
+)
+
+// hurlSections are the request section names; other bracketed lines, such
+// as a JSON array, are body lines.
+var hurlSections = map[string]bool{
+	"QueryStringParams": true,
+	"Query":             true,
+	"FormParams":        true,
+	"Form":              true,
+	"MultipartFormData": true,
+	"Multipart":         true,
+	"Cookies":           true,
+	"BasicAuth":         true,
+	"Options":           true,
+}
+
+func (HurlImporter) Import(r io.Reader) ([]*Request, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var reqs []*Request
+	for i := 0; i < len(lines); {
+		m := matchHurlRequest(lines[i])
+		if m == nil {
+			i++
+			continue
+		}
+
+		req := &Request{
+			Method: m[1],
+			URL:    m[2],
+			Header: Header{},
+		}
+		next, err := parseHurlEntry(req, lines, i+1)
+		if err != nil {
+			return nil, fmt.Errorf("hurl: line %d: %w", i+1, err)
+		}
+		reqs = append(reqs, req)
+		i = next
+	}
+	return reqs, nil
+}
+
+// parseHurlEntry fills req from the lines following its request line and
+// returns the index of the first line after the entry.
+func parseHurlEntry(req *Request, lines []string, i int) (int, error) {
+	section := ""
+	var query, form, cookies []string
+	var body []string
+
+	finish := func() {
+		if len(query) > 0 {
+			sep := "?"
+			if strings.Contains(req.URL, "?") {
+				sep = "&"
+			}
+			req.URL += sep + strings.Join(query, "&")
+		}
+		if len(form) > 0 {
+			req.Header[KeyContentType] = ContentTypeForm
+			req.Body = strings.Join(form, "&")
+		}
+		if len(cookies) > 0 {
+			req.Header[KeyCookie] = strings.Join(cookies, "; ")
+		}
+		if len(body) > 0 {
+			req.Body = hurlBody(body)
+		}
+		req.BodyKind = DetectBodyKind(req.Header[KeyContentType], req.Body)
+	}
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if matchHurlRequest(line) != nil {
+			finish()
+			return i, nil
+		}
+		if hurlResponseLine.MatchString(trimmed) {
+			// Skip the response section up to the next entry.
+			finish()
+			i++
+			for i < len(lines) && matchHurlRequest(lines[i]) == nil {
+				i++
+			}
+			return i, nil
+		}
+		if body != nil {
+			body = append(body, line)
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") && hurlSections[strings.TrimSuffix(trimmed[1:], "]")] {
+			section = trimmed[1 : len(trimmed)-1]
+			continue
+		}
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") ||
+			strings.HasPrefix(trimmed, "<") || strings.HasPrefix(trimmed, "`") {
+			body = append(body, line)
+			continue
+		}
+
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			return 0, fmt.Errorf("unexpected line %q", line)
+		}
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+
+		switch section {
+		case "":
+			req.Header[strings.ToLower(key)] = val
+		case "QueryStringParams", "Query":
+			query = append(query, url.QueryEscape(key)+"="+url.QueryEscape(val))
+		case "FormParams", "Form":
+			form = append(form, url.QueryEscape(key)+"="+url.QueryEscape(val))
+		case "Cookies":
+			cookies = append(cookies, key+"="+val)
+		case "BasicAuth":
+			req.Header[KeyAuthorization] = "Basic " + base64.StdEncoding.EncodeToString([]byte(key+":"+val))
+		}
+	}
+	finish()
+	return i, nil
+}
+
+func matchHurlRequest(line string) []string {
+	m := hurlRequestLine.FindStringSubmatch(line)
+	if m == nil || m[1] == "HTTP" {
+		return nil
+	}
+	return m
+}
+
+func hurlBody(lines []string) string {
+	body := strings.TrimSpace(strings.Join(lines, "\n"))
+	switch {
+	case strings.HasPrefix(body, "```"):
+		// Drop the opening fence (and its optional language) and the closing fence.
+		_, body, _ = strings.Cut(body, "\n")
+		body = strings.TrimSuffix(strings.TrimRight(body, "\n"), "```")
+		return strings.TrimSuffix(body, "\n")
+	case strings.HasPrefix(body, "`") && strings.HasSuffix(body, "`"):
+		return body[1 : len(body)-1]
+	}
+	return body
+}
@@ -0,0 +1,90 @@
+package gcurl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHurlImporter(t *testing.T) {
+	given := "# Create a sloth\n" +
+		"POST https://api.site.com/sloths\n" +
+		"Content-Type: application/json\n" +
+		"{\n" +
+		"  \"name\": \"Sid\"\n" +
+		"}\n" +
+		"HTTP 201\n" +
+		"[Asserts]\n" +
+		"jsonpath \"$.id\" exists\n" +
+		"\n" +
+		"GET https://api.site.com/sloths\n" +
+		"[QueryStringParams]\n" +
+		"kind: two toed\n" +
+		"[Cookies]\n" +
+		"session: abc\n" +
+		"HTTP/1.1 200\n" +
+		"\n" +
+		"POST https://api.site.com/login\n" +
+		"[FormParams]\n" +
+		"user: sid\n" +
+		"\n" +
+		"PUT https://api.site.com/notes/1\n" +
+		"```text\n" +
+		"hello\n" +
+		"world\n" +
+		"```\n"
+
+	actual, err := HurlImporter{}.Import(strings.NewReader(given))
+	require.NoError(t, err)
+	require.Equal(t, []*Request{
+		{
+			Method:   "POST",
+			URL:      "https://api.site.com/sloths",
+			Header:   Header{"content-type": "application/json"},
+			Body:     "{\n  \"name\": \"Sid\"\n}",
+			BodyKind: BodyJSON,
+		},
+		{
+			Method: "GET",
+			URL:    "https://api.site.com/sloths?kind=two+toed",
+			Header: Header{"cookie": "session=abc"},
+		},
+		{
+			Method:   "POST",
+			URL:      "https://api.site.com/login",
+			Header:   Header{"content-type": "application/x-www-form-urlencoded"},
+			Body:     "user=sid",
+			BodyKind: BodyForm,
+		},
+		{
+			Method:   "PUT",
+			URL:      "https://api.site.com/notes/1",
+			Header:   Header{},
+			Body:     "hello\nworld",
+			BodyKind: BodyText,
+		},
+	}, actual)
+}
+
+func TestHurlImporterBody(t *testing.T) {
+	given := "POST https://api.site.com/sloths/bulk\n" +
+		"Content-Type: application/json\n" +
+		"[1, 2]\n" +
+		"HTTP 200\n" +
+		"\n" +
+		"POST https://api.site.com/notes\n" +
+		"Content-Type: text/plain\n" +
+		"```\n" +
+		"HTTP is stateless\n" +
+		"HTTP/2 too\n" +
+		"```\n" +
+		"HTTP/2 201\n"
+
+	actual, err := HurlImporter{}.Import(strings.NewReader(given))
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	require.Equal(t, "[1, 2]", actual[0].Body)
+	require.Equal(t, BodyJSON, actual[0].BodyKind)
+	require.Equal(t, "HTTP is stateless\nHTTP/2 too", actual[1].Body)
+}
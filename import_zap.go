@@ -0,0 +1,80 @@
+package gcurl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ZAPImporter reads OWASP ZAP "Export Messages to File" text exports, in
+// which each message is introduced by a "==== N ==========" line and holds
+// the raw request followed by the raw response.
+type ZAPImporter struct{}
+
+var (
+	zapSeparator  = regexp.MustCompile(`^==== \d+ =+\s*$`)
+	zapStatusLine = regexp.MustCompile(`^HTTP/\d(\.\d)? \d{3}`)
+)
+
+func (ZAPImporter) Import(r io.Reader) ([]*Request, error) {
+	var messages [][]string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if zapSeparator.MatchString(line) {
+			messages = append(messages, nil)
+			continue
+		}
+		if len(messages) > 0 {
+			messages[len(messages)-1] = append(messages[len(messages)-1], line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	reqs := make([]*Request, 0, len(messages))
+	for i, lines := range messages {
+		// The request ends where the response status line starts, which
+		// can only happen after the blank line closing the headers.
+		end, inBody := len(lines), false
+		for j, line := range lines {
+			if inBody && zapStatusLine.MatchString(line) {
+				end = j
+				break
+			}
+			if line == "" {
+				inBody = true
+			}
+		}
+
+		head, body := lines[:end], ""
+		for j, line := range head {
+			if line == "" {
+				body = strings.TrimRight(strings.Join(head[j+1:], "\n"), "\n")
+				head = head[:j]
+				break
+			}
+		}
+		// The body is taken as written, so a stale Content-Length must not
+		// make the request reader wait for more bytes.
+		var headers []string
+		for _, line := range head {
+			if !strings.HasPrefix(strings.ToLower(line), "content-length:") {
+				headers = append(headers, line)
+			}
+		}
+
+		req, err := ParseRawHTTP(strings.NewReader(strings.Join(headers, "\r\n") + "\r\n\r\n"))
+		if err != nil {
+			return nil, fmt.Errorf("zap: message %d: %w", i+1, err)
+		}
+		req.Body = body
+		req.BodyKind = DetectBodyKind(req.Header[KeyContentType], req.Body)
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
@@ -0,0 +1,47 @@
+package gcurl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestZAPImporter(t *testing.T) {
+	given := "==== 1 ==========\n" +
+		"POST http://api.site.com/sloths HTTP/1.1\n" +
+		"Host: api.site.com\n" +
+		"Content-Type: application/x-www-form-urlencoded\n" +
+		"Content-Length: 99\n" +
+		"\n" +
+		"name=sid\n" +
+		"HTTP/1.1 201 Created\n" +
+		"Content-Type: text/plain\n" +
+		"\n" +
+		"created\n" +
+		"==== 2 ==========\n" +
+		"GET http://api.site.com/sloths HTTP/1.1\n" +
+		"Host: api.site.com\n" +
+		"Accept: application/json\n" +
+		"\n" +
+		"HTTP/1.1 200 OK\n" +
+		"\n" +
+		"[]\n"
+
+	actual, err := ZAPImporter{}.Import(strings.NewReader(given))
+	require.NoError(t, err)
+	require.Equal(t, []*Request{
+		{
+			Method:   "POST",
+			URL:      "http://api.site.com/sloths",
+			Header:   Header{"content-type": "application/x-www-form-urlencoded"},
+			Body:     "name=sid",
+			BodyKind: BodyForm,
+		},
+		{
+			Method: "GET",
+			URL:    "http://api.site.com/sloths",
+			Header: Header{"accept": "application/json"},
+		},
+	}, actual)
+}
@@ -0,0 +1,25 @@
+package gcurl
+
+import (
+	"io"
+	"sort"
+)
+
+// Exporter writes a batch of requests in a foreign tool format.
+type Exporter interface {
+	Export(w io.Writer, reqs []*Request) error
+}
+
+// Importer reads requests written in a foreign tool format.
+type Importer interface {
+	Import(r io.Reader) ([]*Request, error)
+}
+
+func sortedKeys(h Header) []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+package gcurl
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONLWriter writes bulk results as JSON Lines, one object per result.
+type JSONLWriter struct {
+	enc *json.Encoder
+}
+
+type jsonlRecord struct {
+	Line    int      `json:"line"`
+	Command string   `json:"command"`
+	Request *Request `json:"request,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &JSONLWriter{enc: enc}
+}
+
+func (w *JSONLWriter) Write(res BulkResult) error {
+	rec := jsonlRecord{Line: res.Line, Command: res.Command, Request: res.Request}
+	if res.Err != nil {
+		rec.Error = res.Err.Error()
+	}
+	return w.enc.Encode(rec)
+}
+
+// BatchJSONLWriter is a ResultSink writing batch results as JSON Lines,
+// without their bodies.
+type BatchJSONLWriter struct {
+	enc *json.Encoder
+}
+
+type batchRecord struct {
+	Index      int         `json:"index"`
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Status     BatchStatus `json:"status"`
+	StatusCode int         `json:"status_code,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+func NewBatchJSONLWriter(w io.Writer) *BatchJSONLWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &BatchJSONLWriter{enc: enc}
+}
+
+func (w *BatchJSONLWriter) Put(res BatchResult) error {
+	rec := batchRecord{Index: res.Index, Status: res.Status}
+	if res.Request != nil {
+		rec.Method, rec.URL = res.Request.Method, res.Request.URL
+	}
+	if res.Response != nil {
+		rec.StatusCode = res.Response.StatusCode
+	}
+	if res.Err != nil {
+		rec.Error = res.Err.Error()
+	}
+	return w.enc.Encode(rec)
+}
+
+// WriteJSONL streams results to w as they arrive. The channel is always
+// drained so producers never block; the first write error is returned.
+func WriteJSONL(w io.Writer, results <-chan BulkResult) error {
+	jw := NewJSONLWriter(w)
+	var err error
+	for res := range results {
+		if err == nil {
+			err = jw.Write(res)
+		}
+	}
+	return err
+}
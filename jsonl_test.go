@@ -0,0 +1,64 @@
+package gcurl
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSONL(t *testing.T) {
+	results := make(chan BulkResult, 2)
+	results <- BulkResult{
+		Line:    1,
+		Command: "curl https://api.site.com/?a=1&b=2",
+		Request: &Request{Method: "GET", URL: "https://api.site.com/?a=1&b=2", Header: Header{}},
+	}
+	results <- BulkResult{Line: 2, Command: "curl", Err: errors.New("boom")}
+	close(results)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteJSONL(buf, results))
+	require.Equal(t,
+		`{"line":1,"command":"curl https://api.site.com/?a=1&b=2","request":{"method":"GET","url":"https://api.site.com/?a=1&b=2","header":{},"skip_tls":false,"timeout":""}}`+"\n"+
+			`{"line":2,"command":"curl","error":"boom"}`+"\n",
+		buf.String())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteJSONLDrainsOnError(t *testing.T) {
+	results := make(chan BulkResult, 3)
+	for i := 1; i <= 3; i++ {
+		results <- BulkResult{Line: i}
+	}
+	close(results)
+
+	require.EqualError(t, WriteJSONL(failingWriter{}, results), "disk full")
+	require.Empty(t, results)
+}
+
+func TestBatchJSONLWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := NewBatchJSONLWriter(buf)
+	require.NoError(t, w.Put(BatchResult{
+		Index:    0,
+		Request:  &Request{Method: "GET", URL: "https://api.site.com/?a=1&b=2"},
+		Response: &Response{Response: &http.Response{StatusCode: http.StatusOK}},
+		Status:   BatchCompleted,
+	}))
+	require.NoError(t, w.Put(BatchResult{
+		Index:   1,
+		Request: &Request{Method: "POST", URL: "https://api.site.com/sloths"},
+		Err:     errors.New("boom"),
+		Status:  BatchFailed,
+	}))
+	require.Equal(t,
+		`{"index":0,"method":"GET","url":"https://api.site.com/?a=1&b=2","status":"completed","status_code":200}`+"\n"+
+			`{"index":1,"method":"POST","url":"https://api.site.com/sloths","status":"failed","error":"boom"}`+"\n",
+		buf.String())
+}
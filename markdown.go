@@ -0,0 +1,91 @@
+package gcurl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MarkdownCommand is a curl command found in a fenced code block.
+type MarkdownCommand struct {
+	File    string
+	Line    int
+	Command string
+	Request *Request
+	// Err is the parse error, prefixed with the file and line.
+	Err error
+}
+
+var markdownShellLangs = map[string]bool{
+	"": true, "bash": true, "sh": true, "shell": true, "zsh": true, "console": true, "curl": true,
+}
+
+// ScanMarkdown parses every curl command found in shell fenced code blocks
+// of a Markdown document. Parse failures are reported per command rather
+// than aborting the scan; the returned error is only set on read failures.
+func ScanMarkdown(r io.Reader, file string) ([]MarkdownCommand, error) {
+	var cmds []MarkdownCommand
+	var fence string
+	var inShell bool
+	var cmd []string
+	var cmdLine int
+
+	flush := func() {
+		if cmd == nil {
+			return
+		}
+		c := MarkdownCommand{File: file, Line: cmdLine, Command: strings.Join(cmd, "\n")}
+		c.Request, c.Err = Parse(c.Command)
+		if c.Err != nil {
+			c.Err = fmt.Errorf("%s:%d: %w", file, cmdLine, c.Err)
+		}
+		cmds = append(cmds, c)
+		cmd = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(text)
+
+		if fence == "" {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fence = trimmed[:3]
+				lang, _, _ := strings.Cut(strings.TrimSpace(trimmed[3:]), " ")
+				inShell = markdownShellLangs[strings.ToLower(lang)]
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) {
+			flush()
+			fence = ""
+			continue
+		}
+		if !inShell {
+			continue
+		}
+
+		if cmd != nil {
+			cmd = append(cmd, text)
+			if !strings.HasSuffix(trimmed, `\`) {
+				flush()
+			}
+			continue
+		}
+
+		trimmed = strings.TrimPrefix(strings.TrimPrefix(trimmed, "$"), " ")
+		if strings.HasPrefix(trimmed, "curl ") {
+			cmd = []string{trimmed}
+			cmdLine = line
+			if !strings.HasSuffix(trimmed, `\`) {
+				flush()
+			}
+		}
+	}
+	flush()
+	return cmds, scanner.Err()
+}
@@ -0,0 +1,45 @@
+package gcurl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanMarkdown(t *testing.T) {
+	given := "# API\n" +
+		"\n" +
+		"```bash\n" +
+		"$ curl https://api.site.com/sloths\n" +
+		"```\n" +
+		"\n" +
+		"```json\n" +
+		"curl https://ignored.example.com\n" +
+		"```\n" +
+		"\n" +
+		"```sh\n" +
+		"# create one\n" +
+		"curl -X POST https://api.site.com/sloths \\\n" +
+		"  -H 'Content-Type: application/json' \\\n" +
+		"  -d '{\"name\": \"Sid\"}'\n" +
+		"curl -H 'broken https://api.site.com\n" +
+		"```\n"
+
+	cmds, err := ScanMarkdown(strings.NewReader(given), "README.md")
+	require.NoError(t, err)
+	require.Len(t, cmds, 3)
+
+	require.Equal(t, 4, cmds[0].Line)
+	require.NoError(t, cmds[0].Err)
+	require.Equal(t, "https://api.site.com/sloths", cmds[0].Request.URL)
+
+	require.Equal(t, 13, cmds[1].Line)
+	require.NoError(t, cmds[1].Err)
+	require.Equal(t, "POST", cmds[1].Request.Method)
+	require.Equal(t, `{"name":"Sid"}`, cmds[1].Request.Body)
+
+	require.Equal(t, 16, cmds[2].Line)
+	require.Error(t, cmds[2].Err)
+	require.True(t, strings.HasPrefix(cmds[2].Err.Error(), "README.md:16: "))
+}
@@ -0,0 +1,146 @@
+package gcurl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MethodPrecedence decides the request method when options disagree,
+// e.g. "-X GET" with "-d" or "-I" with "-X POST".
+type MethodPrecedence int
+
+const (
+	// MethodPrecedenceCurl follows curl: -X always sets the method, data
+	// otherwise implies POST and -I implies HEAD. Combining -I with data
+	// fails with ErrMethodConflict, as curl refuses it.
+	MethodPrecedenceCurl MethodPrecedence = iota
+	// MethodPrecedenceData sends commands with data as POST, overriding
+	// -X and -I.
+	MethodPrecedenceData
+)
+
+// ErrMethodConflict is returned for commands asking for both HEAD and a
+// body, like curl's "You can only select one HTTP request method!".
+var ErrMethodConflict = errors.New("conflicting request methods")
+
+// ErrInvalidMethod is returned for -X values that are not RFC 9110 tokens.
+var ErrInvalidMethod = errors.New("invalid request method")
+
+// WithMethodPrecedence sets how conflicting method options are resolved.
+func WithMethodPrecedence(m MethodPrecedence) ParseOption {
+	return func(p *Parser) { p.methodPrecedence = m }
+}
+
+// Method reasons reported in ParseInfo.MethodReason.
+const (
+	MethodReasonDefault  = "default"
+	MethodReasonExplicit = "explicit"
+	MethodReasonData     = "data"
+	MethodReasonHead     = "head"
+	MethodReasonUpload   = "upload"
+)
+
+// resolveMethod picks the method from the -X value, -I and the presence
+// of data or of an upload, recording the decision in info. The caller
+// rejects data combined with an upload.
+func (p *Parser) resolveMethod(explicit string, head, data, upload bool, info *ParseInfo) (string, error) {
+	var implied []string
+	if head {
+		implied = append(implied, http.MethodHead+" (-I)")
+	}
+	if data {
+		implied = append(implied, http.MethodPost+" (data)")
+	}
+	if upload {
+		implied = append(implied, http.MethodPut+" (-T)")
+	}
+
+	switch {
+	case p.methodPrecedence == MethodPrecedenceData && data:
+		info.MethodReason = MethodReasonData
+		if head || explicit != "" && !strings.EqualFold(explicit, http.MethodPost) {
+			info.MethodConflict = fmt.Sprintf("data overrides %s", overriddenMethod(explicit, head))
+		}
+		return http.MethodPost, nil
+	case explicit != "":
+		info.MethodReason = MethodReasonExplicit
+		if head && !strings.EqualFold(explicit, http.MethodHead) ||
+			(data || upload) && (strings.EqualFold(explicit, http.MethodGet) || strings.EqualFold(explicit, http.MethodHead)) {
+			info.MethodConflict = fmt.Sprintf("-X %s overrides %s", explicit, strings.Join(implied, " and "))
+		}
+		return explicit, nil
+	case head && (data || upload):
+		return "", fmt.Errorf("%w: %s", ErrMethodConflict, strings.Join(implied, " and "))
+	case head:
+		info.MethodReason = MethodReasonHead
+		return http.MethodHead, nil
+	case data:
+		info.MethodReason = MethodReasonData
+		return http.MethodPost, nil
+	case upload:
+		info.MethodReason = MethodReasonUpload
+		return http.MethodPut, nil
+	}
+	info.MethodReason = MethodReasonDefault
+	return http.MethodGet, nil
+}
+
+// wellKnownMethods are the methods whose case is normalized: servers
+// match methods case-sensitively, so others such as cache PURGE are sent
+// as written.
+var wellKnownMethods = map[string]string{
+	"get":     http.MethodGet,
+	"head":    http.MethodHead,
+	"post":    http.MethodPost,
+	"put":     http.MethodPut,
+	"patch":   http.MethodPatch,
+	"delete":  http.MethodDelete,
+	"connect": http.MethodConnect,
+	"options": http.MethodOptions,
+	"trace":   http.MethodTrace,
+
+	"propfind":   MethodPropfind,
+	"proppatch":  MethodProppatch,
+	"mkcol":      MethodMkcol,
+	"copy":       MethodCopy,
+	"move":       MethodMove,
+	"lock":       MethodLock,
+	"unlock":     MethodUnlock,
+	"report":     MethodReport,
+	"mkcalendar": MethodMkcalendar,
+}
+
+// normalizeMethod validates a -X value and upper-cases it if it is a well
+// known method.
+func normalizeMethod(method string) (string, error) {
+	if method == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalidMethod)
+	}
+	for _, c := range method {
+		if !isTokenChar(c) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidMethod, method)
+		}
+	}
+	if m, ok := wellKnownMethods[strings.ToLower(method)]; ok {
+		return m, nil
+	}
+	return method, nil
+}
+
+// isTokenChar reports whether c is a tchar of RFC 9110.
+func isTokenChar(c rune) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", c)
+}
+
+func overriddenMethod(explicit string, head bool) string {
+	if explicit != "" {
+		return "-X " + explicit
+	}
+	return http.MethodHead + " (-I)"
+}
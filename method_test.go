@@ -0,0 +1,80 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMethodPrecedence(t *testing.T) {
+	var tests = []struct {
+		name       string
+		precedence MethodPrecedence
+		given      string
+		method     string
+		reason     string
+		conflict   string
+	}{
+		{"default", MethodPrecedenceCurl, `curl https://api.sloths.com`, "GET", MethodReasonDefault, ""},
+		{"data", MethodPrecedenceCurl, `curl -d 'a=b' https://api.sloths.com`, "POST", MethodReasonData, ""},
+		{"head", MethodPrecedenceCurl, `curl -I https://api.sloths.com`, "HEAD", MethodReasonHead, ""},
+		{"explicit", MethodPrecedenceCurl, `curl -X PUT -d 'a=b' https://api.sloths.com`, "PUT", MethodReasonExplicit, ""},
+		{"explicit before data", MethodPrecedenceCurl, `curl -X GET -d 'a=b' https://api.sloths.com`, "GET", MethodReasonExplicit, "-X GET overrides POST (data)"},
+		{"explicit after data", MethodPrecedenceCurl, `curl -d 'a=b' -X GET https://api.sloths.com`, "GET", MethodReasonExplicit, "-X GET overrides POST (data)"},
+		{"explicit over head", MethodPrecedenceCurl, `curl -I -X POST https://api.sloths.com`, "POST", MethodReasonExplicit, "-X POST overrides HEAD (-I)"},
+		{"explicit over head and data", MethodPrecedenceCurl, `curl -I -X PUT -d 'a=b' https://api.sloths.com`, "PUT", MethodReasonExplicit, "-X PUT overrides HEAD (-I) and POST (data)"},
+		{"data over explicit", MethodPrecedenceData, `curl -X GET -d 'a=b' https://api.sloths.com`, "POST", MethodReasonData, "data overrides -X GET"},
+		{"data over head", MethodPrecedenceData, `curl -I -d 'a=b' https://api.sloths.com`, "POST", MethodReasonData, "data overrides HEAD (-I)"},
+		{"data keeps explicit POST", MethodPrecedenceData, `curl -X POST -d 'a=b' https://api.sloths.com`, "POST", MethodReasonData, ""},
+		{"data without data", MethodPrecedenceData, `curl -X DELETE https://api.sloths.com`, "DELETE", MethodReasonExplicit, ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, info, err := NewParser(WithMethodPrecedence(tt.precedence)).ParseWithInfo(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.method, actual.Method)
+			require.Equal(t, tt.reason, info.MethodReason)
+			require.Equal(t, tt.conflict, info.MethodConflict)
+		})
+	}
+
+	_, err := Parse(`curl -I -d 'a=b' https://api.sloths.com`)
+	require.ErrorIs(t, err, ErrMethodConflict)
+}
+
+func TestParseMethodValidation(t *testing.T) {
+	var tests = []struct {
+		name   string
+		given  string
+		method string
+	}{
+		{"well known lower case", `curl -X delete https://api.sloths.com/1`, "DELETE"},
+		{"well known mixed case", `curl -XPatch https://api.sloths.com/1`, "PATCH"},
+		{"purge", `curl -X PURGE https://cdn.sloths.com/img.png`, "PURGE"},
+		{"mkcol", `curl -X MKCOL https://dav.sloths.com/photos/`, "MKCOL"},
+		{"webdav", `curl -X propfind https://dav.sloths.com/cal/`, "PROPFIND"},
+		{"custom case kept", `curl -X purge https://cdn.sloths.com/img.png`, "purge"},
+		{"token chars", `curl -X 'X-SYNC.v2' https://api.sloths.com`, "X-SYNC.v2"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Parse(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.method, actual.Method)
+		})
+	}
+
+	for _, given := range []string{
+		`curl -X 'GET /' https://api.sloths.com`,
+		`curl -X 'PO(ST' https://api.sloths.com`,
+		`curl -X '' https://api.sloths.com`,
+		`curl -X 'ÉTÉ' https://api.sloths.com`,
+	} {
+		_, err := Parse(given)
+		require.ErrorIs(t, err, ErrInvalidMethod, given)
+	}
+}
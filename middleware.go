@@ -0,0 +1,26 @@
+package gcurl
+
+import "net/http"
+
+// redacted replaces secrets in commands meant for logs.
+const redacted = "REDACTED"
+
+func redact(string) string {
+	return redacted
+}
+
+// CurlLogger returns net/http middleware that reconstructs every incoming
+// request as a curl command, with secrets redacted, and passes it to log
+// before serving the request, for "reproduce this API call" logging. It
+// has the standard middleware signature, so it plugs into routers such
+// as chi as is, and into echo or gin through their net/http wrappers.
+func CurlLogger(log func(r *http.Request, curl string)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if req, err := FromHTTPRequest(r); err == nil {
+				log(r, req.StringRedacted())
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
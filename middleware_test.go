@@ -0,0 +1,40 @@
+package gcurl
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurlLogger(t *testing.T) {
+	var logged []string
+	mw := CurlLogger(func(r *http.Request, curl string) { logged = append(logged, curl) })
+
+	var served string
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		served = string(body)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/login?token=abc&page=1", strings.NewReader("user=sid&password=hunter2"))
+	r.Host = "api.site.com"
+	r.Header.Set("Content-Type", ContentTypeForm)
+	r.Header.Set("Authorization", "Bearer abc")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.Equal(t, "user=sid&password=hunter2", served)
+	require.Equal(t, []string{
+		`curl -H 'authorization: Bearer REDACTED' -H 'content-type: application/x-www-form-urlencoded' ` +
+			`--data-raw 'user=sid&password=REDACTED' 'http://api.site.com/login?token=REDACTED&page=1'`,
+	}, logged)
+
+	// The logged command replays the request.
+	req, err := Parse(logged[0])
+	require.NoError(t, err)
+	require.Equal(t, "POST", req.Method)
+	require.Equal(t, "http://api.site.com/login?token=REDACTED&page=1", req.URL)
+}
@@ -0,0 +1,80 @@
+package gcurl
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DuplicateHeaders selects how a -H header given more than once is
+// parsed.
+type DuplicateHeaders int
+
+const (
+	// DuplicateHeadersAppend keeps every value in MultiHeader so that all
+	// are sent, like curl does. Header holds the last one.
+	DuplicateHeadersAppend DuplicateHeaders = iota
+	// DuplicateHeadersLastWins keeps the last value only.
+	DuplicateHeadersLastWins
+	// DuplicateHeadersFirstWins keeps the first value only.
+	DuplicateHeadersFirstWins
+)
+
+// WithDuplicateHeaders sets how repeated -H headers are parsed.
+func WithDuplicateHeaders(policy DuplicateHeaders) ParseOption {
+	return func(p *Parser) { p.duplicateHeaders = policy }
+}
+
+// addHeader adds a -H header according to the duplicate header policy.
+func (p *Parser) addHeader(req *Request, key, value string) {
+	switch p.duplicateHeaders {
+	case DuplicateHeadersLastWins:
+		req.Header[strings.ToLower(key)] = value
+	case DuplicateHeadersFirstWins:
+		if _, ok := req.Header[strings.ToLower(key)]; !ok {
+			req.Header[strings.ToLower(key)] = value
+		}
+	default:
+		req.AddHeader(key, value)
+	}
+}
+
+// AddHeader adds a header value, keeping the values already set for key
+// in MultiHeader so that repeated headers are all sent.
+func (r *Request) AddHeader(key, value string) {
+	key = strings.ToLower(key)
+	if prev, ok := r.Header[key]; ok {
+		if r.MultiHeader == nil {
+			r.MultiHeader = http.Header{}
+		}
+		ck := http.CanonicalHeaderKey(key)
+		if len(r.MultiHeader[ck]) == 0 {
+			r.MultiHeader[ck] = []string{prev}
+		}
+		r.MultiHeader[ck] = append(r.MultiHeader[ck], value)
+	}
+	r.Header[key] = value
+}
+
+// HeaderValues returns all the values of the header key in command order.
+// Values in MultiHeader are ignored once Header no longer ends with them,
+// that is after the header was overwritten.
+func (r *Request) HeaderValues(key string) []string {
+	v, ok := r.Header[strings.ToLower(key)]
+	if !ok {
+		return nil
+	}
+	if vals := r.MultiHeader[http.CanonicalHeaderKey(key)]; len(vals) > 1 && vals[len(vals)-1] == v {
+		return vals
+	}
+	return []string{v}
+}
+
+// HTTPHeader returns the headers with canonical keys and every value of
+// repeated headers.
+func (r *Request) HTTPHeader() http.Header {
+	h := make(http.Header, len(r.Header))
+	for k := range r.Header {
+		h[http.CanonicalHeaderKey(k)] = append([]string(nil), r.HeaderValues(k)...)
+	}
+	return h
+}
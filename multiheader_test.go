@@ -0,0 +1,81 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRepeatedHeaders(t *testing.T) {
+	req, err := Parse(`curl -H 'Accept: text/html' -H 'X-Sloth: sid' -H 'accept: application/json' -H 'Set-Cookie: a=1' -H 'Set-Cookie: b=2' https://api.site.com`)
+	require.NoError(t, err)
+
+	require.Equal(t, "application/json", req.Header["accept"])
+	require.Equal(t, http.Header{
+		"Accept":     {"text/html", "application/json"},
+		"Set-Cookie": {"a=1", "b=2"},
+	}, req.MultiHeader)
+	require.Equal(t, []string{"text/html", "application/json"}, req.HeaderValues("Accept"))
+	require.Equal(t, []string{"sid"}, req.HeaderValues("x-sloth"))
+	require.Nil(t, req.HeaderValues("X-Missing"))
+	require.Equal(t, http.Header{
+		"Accept":     {"text/html", "application/json"},
+		"Set-Cookie": {"a=1", "b=2"},
+		"X-Sloth":    {"sid"},
+	}, req.HTTPHeader())
+
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"a=1", "b=2"}, hreq.Header.Values("Set-Cookie"))
+
+	again, err := Parse(req.curl())
+	require.NoError(t, err)
+	require.Equal(t, req, again)
+
+	// Overwriting the header drops the earlier values.
+	req.Header["accept"] = "*/*"
+	require.Equal(t, []string{"*/*"}, req.HeaderValues("Accept"))
+}
+
+func TestRedactRepeatedHeaders(t *testing.T) {
+	req, err := Parse(`curl -H 'X-Api-Key: k1' -H 'X-Api-Key: k2' https://api.site.com`)
+	require.NoError(t, err)
+	require.Equal(t, `curl -H 'x-api-key: REDACTED' -H 'x-api-key: REDACTED' https://api.site.com`, req.StringRedacted())
+
+	tokenized, values := Tokenize(req)
+	require.Equal(t, []string{"{{TOKEN_1}}", "{{TOKEN_2}}"}, tokenized.HeaderValues("X-Api-Key"))
+	require.Equal(t, req, Hydrate(tokenized, values))
+}
+
+func TestDuplicateHeaders(t *testing.T) {
+	const cmd = `curl -H 'Accept: text/html' -H 'X-Sloth: sid' -H 'accept: application/json' https://api.site.com`
+	var tests = []struct {
+		name   string
+		policy DuplicateHeaders
+		accept []string
+	}{
+		{"append", DuplicateHeadersAppend, []string{"text/html", "application/json"}},
+		{"last wins", DuplicateHeadersLastWins, []string{"application/json"}},
+		{"first wins", DuplicateHeadersFirstWins, []string{"text/html"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := NewParser(WithDuplicateHeaders(tt.policy)).Parse(cmd)
+			require.NoError(t, err)
+			require.Equal(t, tt.accept, req.HeaderValues("Accept"))
+			require.Equal(t, tt.accept[len(tt.accept)-1], req.Header["accept"])
+			require.Equal(t, "sid", req.Header["x-sloth"])
+			if len(tt.accept) == 1 {
+				require.Nil(t, req.MultiHeader)
+			}
+
+			hreq, err := req.ToHTTPRequest(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.accept, hreq.Header.Values("Accept"))
+		})
+	}
+}
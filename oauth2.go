@@ -0,0 +1,124 @@
+package gcurl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthProvider supplies credentials for outgoing requests, replacing the
+// ones baked into a captured command.
+type AuthProvider interface {
+	Authorize(ctx context.Context, req *http.Request) error
+}
+
+// TokenInvalidator is implemented by providers caching tokens. The executor
+// invalidates the token and retries once when a request is rejected with
+// 401 Unauthorized.
+type TokenInvalidator interface {
+	Invalidate()
+}
+
+// WithAuthProvider authorizes every request through p.
+func WithAuthProvider(p AuthProvider) ExecOption {
+	return func(c *execConfig) { c.auth = p }
+}
+
+// ClientCredentials acquires and caches OAuth2 access tokens using the
+// client credentials grant (RFC 6749, section 4.4) and sends them as
+// Bearer tokens. It is safe for concurrent use.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// Client sends token requests, http.DefaultClient if nil.
+	Client *http.Client
+	// ExpiryDelta renews tokens this long before they expire.
+	ExpiryDelta time.Duration
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func (c *ClientCredentials) Authorize(ctx context.Context, req *http.Request) error {
+	token, err := c.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (c *ClientCredentials) Invalidate() {
+	c.mu.Lock()
+	c.token = ""
+	c.mu.Unlock()
+}
+
+// Token returns a valid access token, fetching a new one when needed.
+// Expiry is measured with the executor's clock, see WithClock.
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := clockNow(ctx)
+	if c.token != "" && (c.expiry.IsZero() || now.Add(c.ExpiryDelta).Before(c.expiry)) {
+		return c.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", ContentTypeForm)
+	req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oauth2: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("oauth2: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oauth2: token request failed: %s: %s", resp.Status, body)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return "", fmt.Errorf("oauth2: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", fmt.Errorf("oauth2: no access_token in response")
+	}
+
+	c.token = tok.AccessToken
+	c.expiry = time.Time{}
+	if tok.ExpiresIn > 0 {
+		c.expiry = now.Add(time.Duration(tok.ExpiresIn) * time.Second)
+	}
+	return c.token, nil
+}
@@ -0,0 +1,90 @@
+package gcurl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCredentials(t *testing.T) {
+	var issued int32
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if user != "sloth" || pass != "s3cr3t" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := atomic.AddInt32(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600,"scope":%q}`, n, r.FormValue("scope"))
+	}))
+	defer tokens.Close()
+
+	// The API revokes the first token to exercise the refresh on 401.
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer api.Close()
+
+	cc := &ClientCredentials{TokenURL: tokens.URL, ClientID: "sloth", ClientSecret: "s3cr3t", Scopes: []string{"read", "write"}}
+
+	resp, err := Do(context.Background(), `curl -H 'Authorization: Bearer stale' `+api.URL, WithAuthProvider(cc))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "ok", resp.Text())
+
+	// The refreshed token is cached.
+	_, err = Do(context.Background(), "curl "+api.URL, WithAuthProvider(cc))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&issued))
+}
+
+func TestClientCredentialsClock(t *testing.T) {
+	var issued int32
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&issued, 1)
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600}`, n)
+	}))
+	defer tokens.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer api.Close()
+
+	cc := &ClientCredentials{TokenURL: tokens.URL, ExpiryDelta: time.Minute}
+	clock := &stubClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	for _, tt := range []struct {
+		elapsed time.Duration
+		token   string
+	}{
+		{0, "Bearer token-1"},
+		{58 * time.Minute, "Bearer token-1"},
+		{time.Minute, "Bearer token-2"},
+	} {
+		clock.now = clock.now.Add(tt.elapsed)
+		resp, err := Do(context.Background(), "curl "+api.URL, WithAuthProvider(cc), WithClock(clock))
+		require.NoError(t, err)
+		require.Equal(t, tt.token, resp.Text())
+	}
+}
+
+func TestClientCredentialsError(t *testing.T) {
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer tokens.Close()
+
+	cc := &ClientCredentials{TokenURL: tokens.URL, ClientID: "sloth"}
+	_, err := cc.Token(context.Background())
+	require.ErrorContains(t, err, "oauth2: token request failed: 401 Unauthorized")
+}
@@ -0,0 +1,60 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// BatchOverride adjusts how one request of a batch is executed, so that a
+// batch can mix quick health checks with slow uploads.
+type BatchOverride struct {
+	// Timeout replaces the request's --max-time when positive.
+	Timeout time.Duration
+	// Retries is how many more times a request failing with an error or
+	// a 5xx status is sent. Requests that aren't idempotent, see
+	// IsIdempotent, are never retried.
+	Retries int
+	// RetryDelay is waited before each retry.
+	RetryDelay time.Duration
+	// Header is added to the request, replacing headers of the same name.
+	Header Header
+}
+
+// apply returns a copy of req with the overrides applied.
+func (o *BatchOverride) apply(req *Request) *Request {
+	req = req.Clone()
+	if o.Timeout > 0 {
+		req.Timeout = FormatDuration(o.Timeout)
+	}
+	for k, v := range o.Header {
+		req.Header.Set(k, v)
+		req.MultiHeader.Del(k)
+	}
+	return req
+}
+
+// retry reports whether the attempt-th attempt of req, which ended with
+// resp and err, should be followed by another one.
+func (o *BatchOverride) retry(req *Request, attempt int, resp *Response, err error) bool {
+	if attempt > o.Retries || !req.IsIdempotent() {
+		return false
+	}
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// wait blocks for d on the configured clock, or until ctx is done.
+func (c *execConfig) wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	done := make(chan struct{})
+	stop := c.afterFunc(d, func() { close(done) })
+	select {
+	case <-ctx.Done():
+		stop()
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchOverride(t *testing.T) {
+	var flaky int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			if atomic.AddInt32(&flaky, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/slow":
+			<-r.Context().Done()
+		}
+		w.Header().Set("X-Env", r.Header.Get("X-Env"))
+	}))
+	defer srv.Close()
+
+	reqs := []*Request{
+		{Method: http.MethodGet, URL: srv.URL + "/flaky", Header: Header{"x-env": "dev"}},
+		{Method: http.MethodGet, URL: srv.URL + "/slow", Header: Header{}},
+		{Method: http.MethodPost, URL: srv.URL + "/unavailable", Header: Header{}},
+		{Method: http.MethodGet, URL: srv.URL + "/health", Header: Header{}},
+	}
+	overrides := map[int]*BatchOverride{
+		0: {Retries: 5, RetryDelay: time.Hour, Header: Header{"X-Env": "staging"}},
+		1: {Timeout: time.Millisecond},
+		2: {Retries: 5},
+	}
+	runner := &BatchRunner{
+		Concurrency: 1,
+		Override:    func(i int, req *Request) *BatchOverride { return overrides[i] },
+		Options:     []ExecOption{WithClock(stubClock{fire: true})},
+	}
+	results := runner.Run(context.Background(), reqs)
+
+	require.Equal(t, BatchCompleted, results[0].Status)
+	require.Equal(t, 3, results[0].Attempts)
+	require.Equal(t, http.StatusOK, results[0].Response.StatusCode)
+	require.Equal(t, "staging", results[0].Response.Header.Get("X-Env"))
+	require.Equal(t, "dev", reqs[0].Header["x-env"], "the request is left untouched")
+
+	require.Equal(t, BatchFailed, results[1].Status)
+	require.ErrorIs(t, results[1].Err, context.DeadlineExceeded)
+
+	// POST isn't idempotent: the 503 is kept.
+	require.Equal(t, 1, results[2].Attempts)
+	require.Equal(t, http.StatusServiceUnavailable, results[2].Response.StatusCode)
+
+	require.Equal(t, 1, results[3].Attempts)
+	require.Equal(t, BatchCompleted, results[3].Status)
+}
@@ -2,31 +2,53 @@ package gcurl
 
 import (
 	"bytes"
-	"encoding/base64"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mattn/go-shellwords"
 )
 
 var ErrNotValidCurlCommand = errors.New("not a valid cURL command")
 
+// ParseError wraps an error that aborted parsing of a command.
+type ParseError struct {
+	Command string
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	cmd := e.Command
+	if len(cmd) > 64 {
+		cmd = cmd[:64] + "..."
+	}
+	return fmt.Sprintf("parse %q: %v", cmd, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 const (
 	// Header map keys
 	KeyContentType   = "content-type"
 	KeyUserAgent     = "user-agent"
 	KeyCookie        = "cookie"
 	KeyAuthorization = "authorization"
+	KeyReferer       = "referer"
 
 	// Content-Types
 	ContentTypeJSON = "application/json"
 	ContentTypeForm = "application/x-www-form-urlencoded"
 )
 
+// Header maps lower-cased header names to values. Its methods ignore the
+// case of names.
 type Header map[string]string
 
 type Request struct {
@@ -36,141 +58,643 @@ type Request struct {
 	Body    string `json:"body,omitempty"`
 	SkipTLS bool   `json:"skip_tls"`
 	Timeout string `json:"timeout"`
+
+	// ConnectTimeout is the --connect-timeout limit on establishing the
+	// connection, TLS handshake included. Timeout, from --max-time, limits
+	// the whole exchange; see TimeoutDuration.
+	ConnectTimeout time.Duration `json:"connect_timeout,omitempty"`
+
+	// Compressed records --compressed: executors decode gzip and deflate
+	// responses, see AcceptEncodingCompressed.
+	Compressed bool `json:"compressed,omitempty"`
+
+	// MultiHeader holds all the values of headers given more than once,
+	// in command order and with canonical keys. Header keeps the last one.
+	MultiHeader http.Header `json:"multi_header,omitempty"`
+
+	// Auth holds the credentials and authentication scheme of the
+	// command. Basic and bearer credentials are also set in Header
+	// unless parsed WithStructuredAuth.
+	Auth *Auth `json:"auth,omitempty"`
+
+	// Proxy is the proxy of -x, ProxyAuth the user:password of
+	// --proxy-user and NoProxy the hosts of --noproxy. See ProxyURL.
+	Proxy     string   `json:"proxy,omitempty"`
+	ProxyAuth string   `json:"proxy_auth,omitempty"`
+	NoProxy   []string `json:"no_proxy,omitempty"`
+
+	// FollowRedirects records -L, MaxRedirects --max-redirs and
+	// TrustRedirects --location-trusted. They have the meaning of the
+	// RedirectPolicy fields, which executors build from them; --max-redirs 0
+	// disables following.
+	FollowRedirects bool `json:"follow_redirects,omitempty"`
+	MaxRedirects    int  `json:"max_redirects,omitempty"`
+	TrustRedirects  bool `json:"trust_redirects,omitempty"`
+	// AutoReferer records the ";auto" suffix of -e/--referer: redirects
+	// are then sent with the previous URL as Referer.
+	AutoReferer bool `json:"auto_referer,omitempty"`
+
+	// CookieFile is the Netscape cookie file given with -b, told apart
+	// from cookies by the lack of '=' like curl does. Its cookies matching
+	// the URL are sent with those of the Cookie header.
+	CookieFile string `json:"cookie_file,omitempty"`
+	// CookieJarFile is the file of -c, where curl saves the cookies of the
+	// response. It is recorded only: executing the request doesn't write it.
+	CookieJarFile string `json:"cookie_jar_file,omitempty"`
+
+	// URLEncoding tells how the URL path and query are encoded when sent.
+	URLEncoding URLEncoding `json:"url_encoding,omitempty"`
+
+	// BodyKind is the detected kind of Body, see DetectBodyKind.
+	BodyKind BodyKind `json:"body_kind,omitempty"`
+
+	// BodyFile is the file given with --data-binary @file, sent as the
+	// body. It is only set when the parser has no FileReader.
+	BodyFile string `json:"body_file,omitempty"`
+
+	// UploadFile is the file of -T, sent as the body of a PUT, or "-" for
+	// standard input. Like curl, the parser appends its base name to URLs
+	// ending with a slash.
+	UploadFile string `json:"upload_file,omitempty"`
+
+	// Form holds the parts of a multipart form, in command order. The
+	// body is built from them when the request is sent.
+	Form []FormField `json:"form,omitempty"`
+
+	// Unrecognized lists the options of the command the parser ignored,
+	// as in ParseInfo.UnknownFlags.
+	Unrecognized []string `json:"unrecognized,omitempty"`
+	// Warnings describe what else of the command was ignored or
+	// overridden while parsing, such as trailing shell text.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Extensions carries application-specific data populated by custom
+	// flag handlers and importers. Values must be JSON-serializable.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Clone returns a deep copy of the request. Extension values are shared.
+func (r *Request) Clone() *Request {
+	c := *r
+	c.Header = make(Header, len(r.Header))
+	for k, v := range r.Header {
+		c.Header[k] = v
+	}
+	if r.MultiHeader != nil {
+		c.MultiHeader = r.MultiHeader.Clone()
+	}
+	if r.Auth != nil {
+		auth := *r.Auth
+		c.Auth = &auth
+	}
+	if r.NoProxy != nil {
+		c.NoProxy = append([]string(nil), r.NoProxy...)
+	}
+	if r.Form != nil {
+		c.Form = append([]FormField(nil), r.Form...)
+		for i, f := range c.Form {
+			if f.Headers != nil {
+				c.Form[i].Headers = append([]string(nil), f.Headers...)
+			}
+		}
+	}
+	if r.Unrecognized != nil {
+		c.Unrecognized = append([]string(nil), r.Unrecognized...)
+	}
+	if r.Warnings != nil {
+		c.Warnings = append([]string(nil), r.Warnings...)
+	}
+	if r.Extensions != nil {
+		c.Extensions = make(map[string]interface{}, len(r.Extensions))
+		for k, v := range r.Extensions {
+			c.Extensions[k] = v
+		}
+	}
+	return &c
+}
+
+// SetExtension stores an extension value, allocating the map if needed.
+func (r *Request) SetExtension(key string, val interface{}) {
+	if r.Extensions == nil {
+		r.Extensions = map[string]interface{}{}
+	}
+	r.Extensions[key] = val
 }
 
+// Parse parses a curl command using the default Parser.
 func Parse(curl string) (*Request, error) {
+	return defaultParser.Parse(curl)
+}
+
+// ParseContext is like Parse but aborts with a *ParseError wrapping
+// ctx.Err() once ctx is done.
+func ParseContext(ctx context.Context, curl string) (*Request, error) {
+	return defaultParser.ParseContext(ctx, curl)
+}
+
+// ParseWithInfo is like Parse but also reports how the command was parsed.
+func ParseWithInfo(curl string) (*Request, *ParseInfo, error) {
+	return defaultParser.ParseWithInfo(curl)
+}
+
+// ParseInfo reports details about how a command was parsed.
+type ParseInfo struct {
+	// ShellSuffix is the unparsed shell text following the curl command,
+	// such as "| jq ." or "> out.json 2>&1".
+	ShellSuffix string
+	// MethodReason tells which option chose the method, one of the
+	// MethodReason constants.
+	MethodReason string
+	// MethodConflict describes the options overridden by the chosen
+	// method, e.g. "-X GET overrides POST (data)". Empty if none.
+	MethodConflict string
+	// UnknownFlags lists the options the parser has no handling for, in
+	// command order. Their values, if any, are not consumed.
+	UnknownFlags []string
+}
+
+func (p *Parser) Parse(curl string) (*Request, error) {
+	return p.ParseContext(context.Background(), curl)
+}
+
+func (p *Parser) ParseContext(ctx context.Context, curl string) (*Request, error) {
+	req, _, err := p.parse(ctx, curl)
+	return req, err
+}
+
+func (p *Parser) ParseWithInfo(curl string) (*Request, *ParseInfo, error) {
+	return p.parse(context.Background(), curl)
+}
+
+func (p *Parser) parse(ctx context.Context, curl string) (*Request, *ParseInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, &ParseError{Command: curl, Err: err}
+	}
 	if strings.Index(curl, "curl ") != 0 {
-		return nil, fmt.Errorf("%q: %w", curl, ErrNotValidCurlCommand)
+		return nil, nil, fmt.Errorf("%q: %w", curl, ErrNotValidCurlCommand)
 	}
 
-	args, err := shellwords.Parse(curl)
+	if p.variables != nil {
+		curl = p.expand(curl)
+	}
+
+	// Tokenizing stops at shell operators such as |, > or ;, whose
+	// position is kept to report the rest of the line.
+	info := &ParseInfo{}
+	line := removeLineContinuations(curl)
+	sw := shellwords.NewParser()
+	args, err := sw.Parse(line)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if sw.Position >= 0 {
+		info.ShellSuffix = strings.TrimSpace(line[sw.Position:])
 	}
 
 	args = sanitize(args)
 	req := &Request{
-		Method: http.MethodGet,
-		Header: Header{},
+		Method:      http.MethodGet,
+		Header:      Header{},
+		URLEncoding: p.urlEncoding,
 	}
 
-	var argType string
+	var argType, customFlag, explicitMethod string
+	var custom FlagHandler
+	var authType AuthType
+	var hasData, hasJSON, head, get bool
+	var maxRedirs *int
 	for _, arg := range args {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, &ParseError{Command: curl, Err: err}
+		}
+
+		if h, ok := p.flagHandler(arg); ok {
+			if h.TakesValue {
+				argType, customFlag, custom = "custom", arg, h
+				continue
+			}
+			if err := h.Handle(req, ""); err != nil {
+				return nil, nil, &ParseError{Command: curl, Err: fmt.Errorf("%s: %w", arg, err)}
+			}
+			continue
+		}
+
 		switch {
-		case isURL(arg):
+		case p.matchURL(arg) && argType == "":
 			req.URL = arg
+		case arg == "--url":
+			argType = "url"
 		case arg == "-A" || arg == "--user-agent":
 			argType = "user-agent"
+		case arg == "-e" || arg == "--referer":
+			argType = "referer"
 		case arg == "-H" || arg == "--header":
 			argType = "header"
-		case arg == "-d" || arg == "--data" || arg == "--data-ascii" || arg == "--data-raw":
-			argType = "data"
-		case arg == "-F" || arg == "--form" || arg == "--form-string":
-			req.Header[KeyContentType] = ContentTypeForm
+		case arg == "-d" || arg == "--data" || arg == "--data-ascii":
 			argType = "data"
+		case arg == "--data-raw":
+			argType = "data-raw"
+		case arg == "--data-binary":
+			argType = "data-binary"
+		case arg == "--data-urlencode":
+			argType = "data-urlencode"
+		case arg == "--json":
+			argType, hasJSON = "json", true
+		case arg == "-F" || arg == "--form":
+			argType = "form"
+		case arg == "--form-string":
+			argType = "form-string"
+		case arg == "-T" || arg == "--upload-file":
+			argType = "upload-file"
 		case arg == "-u" || arg == "--user":
 			argType = "user"
+		case arg == "--oauth2-bearer":
+			argType = "oauth2-bearer"
+		case arg == "--basic":
+			authType = AuthBasic
+		case arg == "--digest":
+			authType = AuthDigest
+		case arg == "--ntlm":
+			authType = AuthNTLM
+		case arg == "--negotiate":
+			authType = AuthNegotiate
+		case arg == "--anyauth":
+			authType = AuthAny
+		case arg == "--aws-sigv4":
+			argType = "aws-sigv4"
 		case arg == "-I" || arg == "--head":
-			req.Method = "HEAD"
+			head = true
+		case arg == "-G" || arg == "--get":
+			get = true
 		case arg == "-X" || arg == "--request":
 			argType = "method"
 		case arg == "-b" || arg == "--cookie":
 			argType = "cookie"
+		case arg == "-c" || arg == "--cookie-jar":
+			argType = "cookie-jar"
 		case arg == "-k" || arg == "--insecure":
 			req.SkipTLS = true
+		case arg == "--compressed":
+			req.Compressed = !p.ignoreCompressed
 		case arg == "-m" || arg == "--max-time":
 			argType = "timeout"
+		case arg == "--connect-timeout":
+			argType = "connect-timeout"
+		case arg == "-L" || arg == "--location":
+			req.FollowRedirects = true
+		case arg == "--location-trusted":
+			req.FollowRedirects, req.TrustRedirects = true, true
+		case arg == "--max-redirs":
+			argType = "max-redirs"
+		case arg == "-x" || arg == "--proxy":
+			argType = "proxy"
+		case arg == "-U" || arg == "--proxy-user":
+			argType = "proxy-user"
+		case arg == "--noproxy":
+			argType = "noproxy"
 		default:
 			switch argType {
+			case "":
+				if len(arg) > 1 && strings.HasPrefix(arg, "-") {
+					info.UnknownFlags = append(info.UnknownFlags, arg)
+				}
+			case "url":
+				// An explicit --url is taken as is, whatever the matcher.
+				req.URL = arg
+				argType = ""
 			case "header":
 				key, val, _ := strings.Cut(arg, ":")
-				req.Header[strings.ToLower(key)] = strings.TrimSpace(val)
+				p.addHeader(req, key, strings.TrimSpace(val))
 				argType = ""
 			case "user-agent":
 				req.Header[KeyUserAgent] = arg
 				argType = ""
-			case "data":
-				if req.Method == http.MethodGet || req.Method == http.MethodHead {
-					req.Method = http.MethodPost
+			case "referer":
+				ref, auto := strings.CutSuffix(arg, ";auto")
+				if ref != "" {
+					req.Header[KeyReferer] = ref
 				}
-
-				if _, ok := req.Header[KeyContentType]; !ok {
-					req.Header[KeyContentType] = ContentTypeForm
+				req.AutoReferer = auto
+				argType = ""
+			case "data", "data-raw", "data-binary", "data-urlencode", "json":
+				var chunk, file string
+				switch argType {
+				case "data-urlencode":
+					chunk, err = p.urlencodeArg(arg)
+				case "json":
+					chunk, file, err = p.dataArg("data-binary", arg)
+				default:
+					chunk, file, err = p.dataArg(argType, arg)
+				}
+				if err != nil {
+					return nil, nil, &ParseError{Command: curl, Err: err}
 				}
+				if file != "" && hasData || req.BodyFile != "" {
+					return nil, nil, &ParseError{Command: curl, Err: ErrDataFileCombined}
+				}
+				hasData = true
 
-				if len(req.Body) == 0 {
-					req.Body = arg
-				} else {
-					req.Body = req.Body + "&" + arg
+				req.BodyFile = file
+				switch {
+				case len(req.Body) == 0:
+					req.Body = chunk
+				case argType == "json":
+					// curl concatenates --json pieces as they are.
+					req.Body += chunk
+				default:
+					req.Body = req.Body + "&" + chunk
 				}
 				argType = ""
+			case "form", "form-string":
+				field, err := parseFormField(arg, argType == "form-string")
+				if err != nil {
+					return nil, nil, &ParseError{Command: curl, Err: err}
+				}
+				req.Form = append(req.Form, field)
+				argType = ""
+			case "upload-file":
+				req.UploadFile = arg
+				argType = ""
 			case "user":
-				req.Header[KeyAuthorization] = "Basic " + base64.StdEncoding.EncodeToString([]byte(arg))
+				userArg(req, arg)
+				argType = ""
+			case "oauth2-bearer":
+				if req.Auth == nil {
+					req.Auth = &Auth{}
+				}
+				req.Auth.Token = arg
+				argType = ""
+			case "aws-sigv4":
+				if _, err := parseSigV4(arg); err != nil {
+					return nil, nil, &ParseError{Command: curl, Err: err}
+				}
+				if req.Auth == nil {
+					req.Auth = &Auth{}
+				}
+				req.Auth.AWSSigV4, authType = arg, AuthAWSSigV4
 				argType = ""
 			case "method":
-				req.Method = arg
+				if explicitMethod, err = normalizeMethod(arg); err != nil {
+					return nil, nil, &ParseError{Command: curl, Err: err}
+				}
 				argType = ""
 			case "cookie":
-				req.Header[KeyCookie] = arg
+				if strings.Contains(arg, "=") {
+					req.Header[KeyCookie] = arg
+				} else {
+					req.CookieFile = arg
+				}
+				argType = ""
+			case "cookie-jar":
+				req.CookieJarFile = arg
 				argType = ""
 			case "timeout":
 				req.Timeout = arg
 				argType = ""
+			case "connect-timeout":
+				if req.ConnectTimeout, err = ParseDuration(arg); err != nil {
+					return nil, nil, &ParseError{Command: curl, Err: fmt.Errorf("invalid --connect-timeout %q: %w", arg, err)}
+				}
+				argType = ""
+			case "max-redirs":
+				n, err := strconv.Atoi(arg)
+				if err != nil || n < -1 {
+					return nil, nil, &ParseError{Command: curl, Err: fmt.Errorf("invalid --max-redirs %q", arg)}
+				}
+				maxRedirs, req.MaxRedirects = &n, n
+				argType = ""
+			case "proxy":
+				req.Proxy = arg
+				argType = ""
+			case "proxy-user":
+				req.ProxyAuth = arg
+				argType = ""
+			case "noproxy":
+				req.NoProxy = parseNoProxy(arg)
+				argType = ""
+			case "custom":
+				if err := custom.Handle(req, arg); err != nil {
+					return nil, nil, &ParseError{Command: curl, Err: fmt.Errorf("%s: %w", customFlag, err)}
+				}
+				argType = ""
 			}
 		}
 	}
 
-	// Format JSON body.
-	if req.Header[KeyContentType] == ContentTypeJSON && req.Body != "" {
+	if err := p.checkUnknownFlags(info.UnknownFlags); err != nil {
+		return nil, nil, &ParseError{Command: curl, Err: err}
+	}
+
+	if maxRedirs != nil && *maxRedirs == 0 {
+		req.FollowRedirects = false
+	}
+
+	// With -G, data goes to the query string like curl does.
+	if get && hasData {
+		if req.BodyFile != "" {
+			return nil, nil, &ParseError{Command: curl, Err: fmt.Errorf("-G with --data-binary @%s: %w", req.BodyFile, ErrNoFileReader)}
+		}
+		req.URL = appendQuery(req.URL, req.Body)
+		req.Body = ""
+		hasData = false
+	}
+
+	getMethod := explicitMethod
+	if getMethod == "" && head {
+		getMethod = http.MethodHead
+	}
+	if hasData && (strings.EqualFold(getMethod, http.MethodGet) || strings.EqualFold(getMethod, http.MethodHead)) {
+		switch p.dataOnGET {
+		case DataOnGETError:
+			return nil, nil, &ParseError{Command: curl, Err: ErrDataOnGET}
+		case DataOnGETAsQuery:
+			req.URL = appendQuery(req.URL, req.Body)
+			req.Body = ""
+			hasData = false
+		}
+	}
+
+	// Handlers may have set the method already; it is only decided here
+	// when the command has method options.
+	hasForm := len(req.Form) > 0
+	if hasForm && get {
+		return nil, nil, &ParseError{Command: curl, Err: fmt.Errorf("%w: -G and form", ErrMethodConflict)}
+	}
+	if hasData && hasForm {
+		return nil, nil, &ParseError{Command: curl, Err: fmt.Errorf("%w: data and form", ErrMethodConflict)}
+	}
+	upload := req.UploadFile != ""
+	if upload && (hasData || hasForm) {
+		return nil, nil, &ParseError{Command: curl, Err: fmt.Errorf("%w: upload and data", ErrMethodConflict)}
+	}
+	if upload {
+		req.URL = uploadURL(req.URL, req.UploadFile)
+	}
+	if explicitMethod != "" || head || hasData || hasForm || upload {
+		if req.Method, err = p.resolveMethod(explicitMethod, head, hasData || hasForm, upload, info); err != nil {
+			return nil, nil, &ParseError{Command: curl, Err: err}
+		}
+	} else {
+		info.MethodReason = MethodReasonDefault
+	}
+
+	// --json sets both headers unless the command does.
+	if hasJSON && hasData {
+		if _, ok := req.Header[KeyContentType]; !ok {
+			req.Header[KeyContentType] = ContentTypeJSON
+		}
+		if !req.Header.Has("accept") {
+			req.Header.Set("accept", ContentTypeJSON)
+		}
+	}
+
+	if req.Compressed && !req.Header.Has("accept-encoding") {
+		req.Header.Set("accept-encoding", AcceptEncodingCompressed)
+	}
+
+	// Like curl, data is sent as a form unless told otherwise. The body is
+	// sniffed when the command doesn't set a Content-Type.
+	contentType, ok := req.Header[KeyContentType]
+	if hasData && !ok {
+		req.Header[KeyContentType] = ContentTypeForm
+	}
+	req.BodyKind = DetectBodyKind(contentType, req.Body)
+	if req.BodyFile != "" || upload {
+		// The file can't be sniffed: trust the Content-Type, even the default.
+		req.BodyKind = contentTypeKind(req.Header[KeyContentType], "")
+		if req.BodyKind == BodyNone {
+			req.BodyKind = BodyBinary
+		}
+	}
+	if hasForm {
+		if !ok {
+			req.Header[KeyContentType] = ContentTypeMultipart
+		}
+		req.BodyKind = BodyMultipart
+	}
+
+	// The Content-Length given applies to the body as written.
+	if err := p.contentLength(req); err != nil {
+		return nil, nil, &ParseError{Command: curl, Err: err}
+	}
+
+	// Format JSON body, unless its length is pinned by Content-Length.
+	if req.Header[KeyContentType] == ContentTypeJSON && req.Body != "" && !req.Header.Has("content-length") {
 		jsonBody, err := formatJSONBody(req.Body)
 		if err != nil {
-			return nil, err
+			return nil, nil, &ParseError{Command: curl, Err: err}
 		}
 		req.Body = jsonBody
 	}
-	return req, nil
+
+	if p.cookieFiles && req.CookieFile != "" {
+		if err := p.loadCookieFile(req); err != nil {
+			return nil, nil, &ParseError{Command: curl, Err: err}
+		}
+	}
+
+	p.finishAuth(req, authType)
+	if p.userinfoAuth {
+		userinfoToAuth(req)
+	}
+
+	if p.urlPolicy != nil && req.URL != "" {
+		if err := p.urlPolicy.Check(req.URL); err != nil {
+			return nil, nil, &ParseError{Command: curl, Err: err}
+		}
+	}
+	if p.urlPolicy != nil {
+		if err := p.urlPolicy.checkProxy(req); err != nil {
+			return nil, nil, &ParseError{Command: curl, Err: err}
+		}
+	}
+
+	p.headerFilter.Apply(req.Header)
+	if err := checkHeaders(req, p.stripControlChars); err != nil {
+		return nil, nil, &ParseError{Command: curl, Err: err}
+	}
+
+	req.Unrecognized = info.UnknownFlags
+	if info.MethodConflict != "" {
+		req.Warnings = append(req.Warnings, info.MethodConflict)
+	}
+	if info.ShellSuffix != "" {
+		req.Warnings = append(req.Warnings, "ignored shell text "+strconv.Quote(info.ShellSuffix))
+	}
+	return req, info, nil
 }
 
+// formatJSONBody removes the insignificant whitespace of body, keeping its
+// values, numbers and key order as written.
 func formatJSONBody(body string) (string, error) {
-	data := make(map[string]interface{})
-	if err := json.Unmarshal([]byte(body), &data); err != nil {
-		return "", err
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, []byte(body)); err != nil {
+		return "", fmt.Errorf("invalid JSON body: %w", err)
 	}
+	return buf.String(), nil
+}
 
+// encodeJSON encodes v compactly without HTML escaping.
+func encodeJSON(v interface{}) (string, error) {
 	buf := &bytes.Buffer{}
 	enc := json.NewEncoder(buf)
 	enc.SetEscapeHTML(false)
-	if err := enc.Encode(data); err != nil {
+	if err := enc.Encode(v); err != nil {
 		return "", err
 	}
 	return strings.ReplaceAll(buf.String(), "\n", ""), nil
 }
 
-func sanitize(args []string) []string {
-	res := make([]string, 0)
-	for _, arg := range args {
-		arg = strings.TrimSpace(arg)
-		if arg == "\n" {
+// removeLineContinuations drops backslash-newline sequences outside single
+// quotes, as the shell does. Arguments are otherwise kept byte for byte.
+func removeLineContinuations(cmd string) string {
+	var b strings.Builder
+	var single, double bool
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case c == '\\' && !single && strings.HasPrefix(cmd[i+1:], "\n"):
+			i++
+			continue
+		case c == '\\' && !single && strings.HasPrefix(cmd[i+1:], "\r\n"):
+			i += 2
 			continue
+		case c == '\\' && !single && i+1 < len(cmd):
+			b.WriteByte(c)
+			i++
+			c = cmd[i]
+		case c == '\'' && !double:
+			single = !single
+		case c == '"' && !single:
+			double = !double
 		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
 
-		// Remove new lines characters.
-		if strings.Contains(arg, "\n") {
-			arg = strings.ReplaceAll(arg, "\n", "")
-		}
+// shortBoolFlags are the letters of curl's short options taking no value,
+// which may be bundled as in -sSL. Digits are left out so that negative
+// numbers given as values aren't split.
+const shortBoolFlags = "afgGiIjJklLMnNOpqRsSvVZ"
 
+func sanitize(args []string) []string {
+	res := make([]string, 0)
+	for _, arg := range args {
 		// Split method when -XMETHOD are concatenated.
 		if strings.HasPrefix(arg, "-X") && len(arg) > 2 {
 			res = append(res, arg[0:2])
 			res = append(res, arg[2:])
 			continue
 		}
+		// Split bundled short options, e.g. -sSL.
+		if len(arg) > 2 && arg[0] == '-' && strings.Trim(arg[1:], shortBoolFlags) == "" {
+			for _, c := range arg[1:] {
+				res = append(res, "-"+string(c))
+			}
+			continue
+		}
 		res = append(res, arg)
 	}
 	return res
 }
-
-func isURL(u string) bool {
-	matched, err := regexp.MatchString("^https?://.*$", u)
-	return matched && err == nil
-}
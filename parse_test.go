@@ -1,6 +1,8 @@
 package gcurl
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -51,6 +53,7 @@ func TestParse(t *testing.T) {
 				Header: map[string]string{
 					"accept-encoding": "gzip",
 				},
+				Compressed: true,
 			},
 		},
 		{
@@ -66,20 +69,22 @@ func TestParse(t *testing.T) {
 			"url encoded data",
 			`curl -d "foo=bar" https://api.site.com/sloth/4`,
 			&Request{
-				Method: http.MethodPost,
-				URL:    "https://api.site.com/sloth/4",
-				Header: map[string]string{"content-type": "application/x-www-form-urlencoded"},
-				Body:   "foo=bar",
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com/sloth/4",
+				Header:   map[string]string{"content-type": "application/x-www-form-urlencoded"},
+				Body:     "foo=bar",
+				BodyKind: BodyForm,
 			},
 		},
 		{
 			"JSON",
 			`curl -d '{"hello": "world"}' -H 'content-type: application/json' https://api.site.com/sloth/4`,
 			&Request{
-				Method: http.MethodPost,
-				URL:    "https://api.site.com/sloth/4",
-				Header: map[string]string{"content-type": "application/json"},
-				Body:   `{"hello":"world"}`,
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com/sloth/4",
+				Header:   map[string]string{"content-type": "application/json"},
+				Body:     `{"hello":"world"}`,
+				BodyKind: BodyJSON,
 			},
 		},
 		{
@@ -112,6 +117,8 @@ func TestParse(t *testing.T) {
 				Method: http.MethodGet,
 				URL:    "https://api.site.com/users?token=admin",
 				Header: map[string]string{},
+
+				FollowRedirects: true,
 			},
 		},
 		{
@@ -129,10 +136,11 @@ func TestParse(t *testing.T) {
 			"repeated data fields",
 			`curl -d 'foo=bar&bar=foo' -d 'q=GoogleQuery' https://api.site.com/sloth/4`,
 			&Request{
-				Method: http.MethodPost,
-				URL:    "https://api.site.com/sloth/4",
-				Header: map[string]string{"content-type": "application/x-www-form-urlencoded"},
-				Body:   "foo=bar&bar=foo&q=GoogleQuery",
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com/sloth/4",
+				Header:   map[string]string{"content-type": "application/x-www-form-urlencoded"},
+				Body:     "foo=bar&bar=foo&q=GoogleQuery",
+				BodyKind: BodyForm,
 			},
 		},
 		{
@@ -144,13 +152,307 @@ func TestParse(t *testing.T) {
 				Header: map[string]string{"authorization": "Token some-custom-auth"},
 			},
 		},
+		{
+			"oauth2 bearer",
+			"curl --oauth2-bearer 's3cr3t.t0k3n' https://api.site.com/sloth/4",
+			&Request{
+				Method: http.MethodGet,
+				URL:    "https://api.site.com/sloth/4",
+				Header: map[string]string{"authorization": "Bearer s3cr3t.t0k3n"},
+				Auth:   &Auth{Type: AuthBearer, Token: "s3cr3t.t0k3n"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Parse(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestRequestExtensions(t *testing.T) {
+	req, err := Parse("curl https://api.site.com")
+	require.NoError(t, err)
+	require.Nil(t, req.Extensions)
+
+	req.SetExtension("team", "sloths")
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"extensions":{"team":"sloths"}`)
+}
+
+func TestParseContext(t *testing.T) {
+	actual, err := ParseContext(context.Background(), "curl https://api.site.com")
+	require.NoError(t, err)
+	require.Equal(t, "https://api.site.com", actual.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ParseContext(ctx, "curl https://api.site.com")
+	require.ErrorIs(t, err, context.Canceled)
+
+	var perr *ParseError
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "curl https://api.site.com", perr.Command)
+	require.EqualError(t, err, `parse "curl https://api.site.com": context canceled`)
+}
+
+func TestRequestClone(t *testing.T) {
+	req, err := Parse("curl -H 'Accept: */*' https://api.site.com")
+	require.NoError(t, err)
+	req.SetExtension("team", "sloths")
+
+	clone := req.Clone()
+	require.Equal(t, req, clone)
+
+	clone.Header["accept"] = "text/plain"
+	clone.SetExtension("team", "koalas")
+	require.Equal(t, "*/*", req.Header["accept"])
+	require.Equal(t, "sloths", req.Extensions["team"])
+}
+
+// TestParseQuoting is a regression corpus of shell quoting idioms found in
+// real commands. Bodies must survive byte for byte.
+func TestParseQuoting(t *testing.T) {
+	var tests = []struct {
+		name  string
+		given string
+		body  string
+	}{
+		{"escaped single quote", `curl -d '{"a":"it'\''s"}' https://api.site.com`, `{"a":"it's"}`},
+		{"double quoted apostrophe", `curl -d "it's" https://api.site.com`, `it's`},
+		{"adjacent quoting", `curl -d 'a'"'"'b' https://api.site.com`, `a'b`},
+		{"double quotes in single quotes", `curl -d 'say "hi"' https://api.site.com`, `say "hi"`},
+		{"escaped double quotes", `curl -d "say \"hi\"" https://api.site.com`, `say "hi"`},
+		{"escaped backslash", `curl -d "a\\b" https://api.site.com`, `a\b`},
+		{"backslash in single quotes", `curl -d 'a\b\n' https://api.site.com`, `a\b\n`},
+		{"dollar in single quotes", `curl -d '$HOME ${x}' https://api.site.com`, `$HOME ${x}`},
+		{"surrounding spaces", `curl -d '  padded  ' https://api.site.com`, `  padded  `},
+		{"newlines", "curl -d 'line 1\nline 2\n' https://api.site.com", "line 1\nline 2\n"},
+		{"continuation", "curl \\\n  -d 'a=1' \\\n  https://api.site.com", "a=1"},
+		{"continuation with CRLF", "curl \\\r\n  -d 'a=1' \\\r\n  https://api.site.com", "a=1"},
+		{"continuation in double quotes", "curl -d \"a\\\nb\" https://api.site.com", "ab"},
+		{"backslash newline in single quotes", "curl -d 'a\\\nb' https://api.site.com", "a\\\nb"},
+		{"unquoted escapes", `curl -d it\'s\ ok https://api.site.com`, `it's ok`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Parse(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.body, actual.Body)
+			require.Equal(t, "https://api.site.com", actual.URL)
+		})
 	}
+}
+
+func TestParseURLFlag(t *testing.T) {
+	var tests = []struct {
+		name  string
+		given string
+		url   string
+		body  string
+	}{
+		{"url flag", `curl --url https://api.site.com/sloths`, "https://api.site.com/sloths", ""},
+		{"url flag first", `curl --url 'https://api.site.com/sloths' -H 'X-Sloth: sid' -d a=1`, "https://api.site.com/sloths", "a=1"},
+		{"unmatched url", `curl --url 'api.site.com/sloths'`, "api.site.com/sloths", ""},
+		{"url-like value", `curl -d 'https://cb.site.com' --url https://api.site.com/hooks`, "https://api.site.com/hooks", "https://cb.site.com"},
+	}
+
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			actual, err := Parse(tt.given)
 			require.NoError(t, err)
+			require.Equal(t, tt.url, actual.URL)
+			require.Equal(t, tt.body, actual.Body)
+		})
+	}
+}
+
+func TestParseMultiline(t *testing.T) {
+	// As copied by Chrome DevTools and pasted from API docs.
+	given := "curl 'https://api.site.com/graphql' \\\n" +
+		"  -H 'accept: */*' \\\n" +
+		"  -H 'content-type: application/json' \\\r\n" +
+		"  --data-raw '{\"query\":\"{ sloths { name } }\"}' \\\n" +
+		"  --compressed"
+
+	req, err := Parse(given)
+	require.NoError(t, err)
+	require.Equal(t, &Request{
+		Method: "POST",
+		URL:    "https://api.site.com/graphql",
+		Header: Header{
+			"accept":          "*/*",
+			"accept-encoding": AcceptEncodingCompressed,
+			"content-type":    ContentTypeJSON,
+		},
+		Body:       `{"query":"{ sloths { name } }"}`,
+		BodyKind:   BodyGraphQL,
+		Compressed: true,
+	}, req)
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	req, err := Parse(`curl -X GET -d a=1 --retry 3 --verbose https://api.site.com | jq .`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"--retry", "--verbose"}, req.Unrecognized)
+	require.Equal(t, []string{"-X GET overrides POST (data)", `ignored shell text "| jq ."`}, req.Warnings)
+
+	req, err = Parse(`curl -H 'Accept: */*' https://api.site.com`)
+	require.NoError(t, err)
+	require.Nil(t, req.Unrecognized)
+	require.Nil(t, req.Warnings)
+}
+
+func TestParseWithInfoShellSuffix(t *testing.T) {
+	var tests = []struct {
+		given  string
+		url    string
+		suffix string
+	}{
+		{`curl -s https://api.site.com/sloths | jq .`, "https://api.site.com/sloths", "| jq ."},
+		{`curl https://api.site.com/sloths > out.json 2>&1`, "https://api.site.com/sloths", "> out.json 2>&1"},
+		{`curl https://api.site.com/sloths 2>/dev/null`, "https://api.site.com/sloths", "2>/dev/null"},
+		{"curl \\\n  https://api.site.com/sloths \\\n  | jq '.[] | .name'", "https://api.site.com/sloths", "| jq '.[] | .name'"},
+		{`curl -d 'a|b>c' https://api.site.com/sloths`, "https://api.site.com/sloths", ""},
+		{`curl https://api.site.com/sloths`, "https://api.site.com/sloths", ""},
+	}
+
+	for _, tt := range tests {
+		req, info, err := ParseWithInfo(tt.given)
+		require.NoError(t, err, tt.given)
+		require.Equal(t, tt.url, req.URL, tt.given)
+		require.Equal(t, tt.suffix, info.ShellSuffix, tt.given)
+	}
+}
+
+func TestParseJSONFlag(t *testing.T) {
+	files := FileReaderFunc(func(name string) ([]byte, error) {
+		return []byte(`{"name": "sid"}`), nil
+	})
+	var tests = []struct {
+		name     string
+		given    string
+		opts     []ParseOption
+		expected *Request
+	}{
+		{
+			"inline",
+			`curl --json '{"a": 1}' https://api.site.com`,
+			nil,
+			&Request{
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com",
+				Header:   Header{"content-type": ContentTypeJSON, "accept": ContentTypeJSON},
+				Body:     `{"a":1}`,
+				BodyKind: BodyJSON,
+			},
+		},
+		{
+			"array",
+			`curl --json '[1, 2]' https://api.site.com`,
+			nil,
+			&Request{
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com",
+				Header:   Header{"content-type": ContentTypeJSON, "accept": ContentTypeJSON},
+				Body:     `[1,2]`,
+				BodyKind: BodyJSON,
+			},
+		},
+		{
+			"numbers as written",
+			`curl --json '{"b": 1.0, "a": 12345678901234567890}' https://api.site.com`,
+			nil,
+			&Request{
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com",
+				Header:   Header{"content-type": ContentTypeJSON, "accept": ContentTypeJSON},
+				Body:     `{"b":1.0,"a":12345678901234567890}`,
+				BodyKind: BodyJSON,
+			},
+		},
+		{
+			"array with data",
+			`curl -H 'Content-Type: application/json' -d '[1, 2]' https://api.site.com`,
+			nil,
+			&Request{
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com",
+				Header:   Header{"content-type": ContentTypeJSON},
+				Body:     `[1,2]`,
+				BodyKind: BodyJSON,
+			},
+		},
+		{
+			"concatenated with custom accept",
+			`curl -H 'Accept: text/plain' --json '{"a":' --json '1}' https://api.site.com`,
+			nil,
+			&Request{
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com",
+				Header:   Header{"content-type": ContentTypeJSON, "accept": "text/plain"},
+				Body:     `{"a":1}`,
+				BodyKind: BodyJSON,
+			},
+		},
+		{
+			"file",
+			`curl --json @sid.json https://api.site.com`,
+			nil,
+			&Request{
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com",
+				Header:   Header{"content-type": ContentTypeJSON, "accept": ContentTypeJSON},
+				BodyFile: "sid.json",
+				BodyKind: BodyJSON,
+			},
+		},
+		{
+			"stdin",
+			`curl -X PUT --json @- https://api.site.com`,
+			nil,
+			&Request{
+				Method:   http.MethodPut,
+				URL:      "https://api.site.com",
+				Header:   Header{"content-type": ContentTypeJSON, "accept": ContentTypeJSON},
+				BodyFile: "-",
+				BodyKind: BodyJSON,
+			},
+		},
+		{
+			"file read while parsing",
+			`curl --json @sid.json https://api.site.com`,
+			[]ParseOption{WithFileReader(files)},
+			&Request{
+				Method:   http.MethodPost,
+				URL:      "https://api.site.com",
+				Header:   Header{"content-type": ContentTypeJSON, "accept": ContentTypeJSON},
+				Body:     `{"name":"sid"}`,
+				BodyKind: BodyJSON,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewParser(tt.opts...).Parse(tt.given)
+			require.NoError(t, err)
 			require.Equal(t, tt.expected, actual)
 		})
 	}
 }
+
+func TestParseInvalidJSON(t *testing.T) {
+	_, err := Parse(`curl --json '{"a":' https://api.site.com`)
+	var perr *ParseError
+	require.ErrorAs(t, err, &perr)
+	require.ErrorContains(t, err, "invalid JSON body")
+}
@@ -0,0 +1,156 @@
+package gcurl
+
+import (
+	"strings"
+	"sync"
+)
+
+// Parser parses curl commands with a fixed configuration. A Parser is
+// immutable once created and safe for concurrent use.
+type Parser struct {
+	variables    map[string]string
+	handlers     map[string]FlagHandler
+	headerFilter HeaderFilter
+	urlPolicy    *URLPolicy
+
+	stripControlChars bool
+	dataOnGET         DataOnGET
+	methodPrecedence  MethodPrecedence
+	fileReader        FileReader
+	urlMatcher        URLMatcher
+	userinfoAuth      bool
+	urlEncoding       URLEncoding
+
+	contentLengthOverride bool
+	duplicateHeaders      DuplicateHeaders
+	cookieFiles           bool
+	strict                bool
+	structuredAuth        bool
+	ignoreCompressed      bool
+}
+
+// ParseOption configures a Parser.
+type ParseOption func(*Parser)
+
+// FlagHandler applies a custom curl option to the request being parsed.
+type FlagHandler struct {
+	// TakesValue reports whether the flag consumes the next argument.
+	TakesValue bool
+	// Handle is called with the flag value, or "" if TakesValue is false.
+	Handle func(req *Request, value string) error
+}
+
+var defaultParser = NewParser()
+
+func NewParser(opts ...ParseOption) *Parser {
+	p := &Parser{
+		handlers: map[string]FlagHandler{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithVariables expands $NAME and ${NAME} references in the command using
+// vars. References to unknown variables are left untouched.
+func WithVariables(vars map[string]string) ParseOption {
+	return func(p *Parser) {
+		if p.variables == nil {
+			p.variables = map[string]string{}
+		}
+		for k, v := range vars {
+			p.variables[k] = v
+		}
+	}
+}
+
+// WithFlagHandler registers a handler for a curl option, taking precedence
+// over the built-in handling of that option.
+func WithFlagHandler(flag string, h FlagHandler) ParseOption {
+	return func(p *Parser) {
+		p.handlers[flag] = h
+	}
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]FlagHandler{}
+)
+
+// RegisterFlag registers a handler for a curl option with every parser,
+// including the one behind Parse, so that applications can support custom
+// or newer options. Handlers given with WithFlagHandler take precedence.
+// It panics if flag doesn't start with "-" or h has no Handle function.
+func RegisterFlag(flag string, h FlagHandler) {
+	if !strings.HasPrefix(flag, "-") || h.Handle == nil {
+		panic("gcurl: invalid RegisterFlag(" + flag + ")")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[flag] = h
+}
+
+// flagHandler returns the handler of flag, if any.
+func (p *Parser) flagHandler(flag string) (FlagHandler, bool) {
+	if h, ok := p.handlers[flag]; ok {
+		return h, true
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	h, ok := registry[flag]
+	return h, ok
+}
+
+// expand substitutes known variables in cmd the way a shell would: not
+// inside single quotes, and escaped when inside double quotes.
+func (p *Parser) expand(cmd string) string {
+	var b strings.Builder
+	var single, double bool
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case c == '\\' && !single && i+1 < len(cmd):
+			b.WriteByte(c)
+			b.WriteByte(cmd[i+1])
+			i++
+			continue
+		case c == '\'' && !double:
+			single = !single
+		case c == '"' && !single:
+			double = !double
+		case c == '$' && !single:
+			name, n := variableName(cmd[i+1:])
+			if v, ok := p.variables[name]; ok && n > 0 {
+				if double {
+					v = doubleQuoteEscaper.Replace(v)
+				}
+				b.WriteString(v)
+				i += n
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+var doubleQuoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+
+// variableName reads a NAME or {NAME} reference, returning the name and
+// the number of bytes it spans.
+func variableName(s string) (string, int) {
+	if strings.HasPrefix(s, "{") {
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "", 0
+		}
+		return s[1:end], end + 1
+	}
+
+	n := 0
+	for n < len(s) && (s[n] == '_' || s[n] >= 'a' && s[n] <= 'z' || s[n] >= 'A' && s[n] <= 'Z' || n > 0 && s[n] >= '0' && s[n] <= '9') {
+		n++
+	}
+	return s[:n], n
+}
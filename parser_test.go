@@ -0,0 +1,151 @@
+package gcurl
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParserVariables(t *testing.T) {
+	p := NewParser(WithVariables(map[string]string{
+		"HOST":  "https://api.site.com",
+		"TOKEN": `s3"cr3t`,
+	}))
+
+	actual, err := p.Parse(`curl -H "Authorization: Bearer $TOKEN" -d '{"cost":"$5"}' ${HOST}/sloths?q=$UNKNOWN`)
+	require.NoError(t, err)
+	require.Equal(t, &Request{
+		Method: http.MethodPost,
+		URL:    "https://api.site.com/sloths?q=$UNKNOWN",
+		Header: Header{
+			"authorization": `Bearer s3"cr3t`,
+			"content-type":  ContentTypeForm,
+		},
+		Body:     `{"cost":"$5"}`,
+		BodyKind: BodyJSON,
+	}, actual)
+}
+
+func TestParserFlagHandler(t *testing.T) {
+	p := NewParser(
+		WithFlagHandler("--trace-id", FlagHandler{
+			TakesValue: true,
+			Handle: func(req *Request, value string) error {
+				req.Header["x-trace-id"] = value
+				return nil
+			},
+		}),
+		WithFlagHandler("--fail", FlagHandler{
+			Handle: func(req *Request, value string) error {
+				req.SetExtension("fail", true)
+				return nil
+			},
+		}),
+		WithFlagHandler("--reject", FlagHandler{
+			Handle: func(req *Request, value string) error {
+				return errors.New("rejected")
+			},
+		}),
+	)
+
+	actual, err := p.Parse(`curl --fail --trace-id abc123 https://api.site.com`)
+	require.NoError(t, err)
+	require.Equal(t, "abc123", actual.Header["x-trace-id"])
+	require.Equal(t, true, actual.Extensions["fail"])
+
+	_, err = p.Parse(`curl --reject https://api.site.com`)
+	require.EqualError(t, err, `parse "curl --reject https://api.site.com": --reject: rejected`)
+	var perr *ParseError
+	require.ErrorAs(t, err, &perr)
+
+	_, err = NewParser(WithFlagHandler("--tenant", FlagHandler{
+		TakesValue: true,
+		Handle: func(req *Request, value string) error {
+			return errors.New("rejected")
+		},
+	})).Parse(`curl --tenant sloths https://api.site.com`)
+	require.ErrorAs(t, err, &perr)
+	require.ErrorContains(t, err, "--tenant: rejected")
+}
+
+func TestRegisterFlag(t *testing.T) {
+	RegisterFlag("--registered-tenant", FlagHandler{
+		TakesValue: true,
+		Handle: func(req *Request, value string) error {
+			req.Header["x-tenant"] = value
+			return nil
+		},
+	})
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, "--registered-tenant")
+		registryMu.Unlock()
+	})
+
+	actual, err := Parse(`curl --registered-tenant sloths https://api.site.com`)
+	require.NoError(t, err)
+	require.Equal(t, "sloths", actual.Header["x-tenant"])
+
+	// Parser handlers take precedence.
+	actual, err = NewParser(WithFlagHandler("--registered-tenant", FlagHandler{
+		TakesValue: true,
+		Handle: func(req *Request, value string) error {
+			req.Header["x-tenant"] = "override"
+			return nil
+		},
+	})).Parse(`curl --registered-tenant sloths https://api.site.com`)
+	require.NoError(t, err)
+	require.Equal(t, "override", actual.Header["x-tenant"])
+
+	require.Panics(t, func() { RegisterFlag("tenant", FlagHandler{Handle: func(*Request, string) error { return nil }}) })
+	require.Panics(t, func() { RegisterFlag("--tenant", FlagHandler{}) })
+}
+
+func TestParserUnknownFlags(t *testing.T) {
+	_, info, err := NewParser().ParseWithInfo(`curl -s --verbose -H 'Accept: */*' --retry 3 https://api.site.com -`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"-s", "--verbose", "--retry"}, info.UnknownFlags)
+
+	_, info, err = NewParser().ParseWithInfo(`curl -H 'Accept: */*' https://api.site.com`)
+	require.NoError(t, err)
+	require.Empty(t, info.UnknownFlags)
+}
+
+func TestParserBundledFlags(t *testing.T) {
+	req, info, err := NewParser().ParseWithInfo(`curl -sSL -kI --max-redirs -1 https://api.site.com`)
+	require.NoError(t, err)
+	require.True(t, req.FollowRedirects)
+	require.True(t, req.SkipTLS)
+	require.Equal(t, http.MethodHead, req.Method)
+	require.Equal(t, -1, req.MaxRedirects)
+	require.Equal(t, []string{"-s", "-S"}, info.UnknownFlags)
+
+	// Options taking a value aren't split.
+	req, err = Parse(`curl -sH 'Accept: */*' https://api.site.com`)
+	require.NoError(t, err)
+	require.Empty(t, req.Header)
+}
+
+func TestParserConcurrentUse(t *testing.T) {
+	p := NewParser(WithVariables(map[string]string{"ID": "4"}))
+
+	urls := make([]string, 8)
+	wg := &sync.WaitGroup{}
+	for i := range urls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if actual, err := p.Parse("curl https://api.site.com/sloth/$ID"); err == nil {
+				urls[i] = actual.URL
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, u := range urls {
+		require.Equal(t, "https://api.site.com/sloth/4", u)
+	}
+}
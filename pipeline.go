@@ -0,0 +1,33 @@
+package gcurl
+
+import (
+	"context"
+	"fmt"
+	"net/http/cookiejar"
+)
+
+// RunPipeline executes reqs in order, stopping at the first failure. The
+// responses of the completed steps are returned along with the error.
+// Use WithSessionCookies to carry cookies set by earlier steps (e.g. a
+// login) over to the following ones.
+func RunPipeline(ctx context.Context, reqs []*Request, opts ...ExecOption) ([]*Response, error) {
+	cfg := newExecConfig(opts)
+	defer cfg.closeIdleConnections()
+	if cfg.sessionCookies && cfg.jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+		cfg.jar = jar
+	}
+
+	resps := make([]*Response, 0, len(reqs))
+	for i, req := range reqs {
+		resp, err := req.do(ctx, cfg)
+		if err != nil {
+			return resps, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		resps = append(resps, resp)
+	}
+	return resps, nil
+}
@@ -0,0 +1,54 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPipelineSessionCookies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "sloth", Path: "/"})
+		case "/me":
+			c, err := r.Cookie("session")
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(c.Value))
+		}
+	}))
+	defer srv.Close()
+
+	var reqs []*Request
+	for _, cmd := range []string{"curl -d 'user=sid' " + srv.URL + "/login", "curl " + srv.URL + "/me"} {
+		req, err := Parse(cmd)
+		require.NoError(t, err)
+		reqs = append(reqs, req)
+	}
+
+	resps, err := RunPipeline(context.Background(), reqs)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, resps[1].StatusCode)
+
+	resps, err = RunPipeline(context.Background(), reqs, WithSessionCookies())
+	require.NoError(t, err)
+	require.Len(t, resps, 2)
+	require.Equal(t, http.StatusOK, resps[1].StatusCode)
+	require.Equal(t, "sloth", resps[1].Text())
+}
+
+func TestRunPipelineStopsOnError(t *testing.T) {
+	reqs := []*Request{
+		{Method: "GET", URL: "http://[::1", Header: Header{}},
+		{Method: "GET", URL: "http://localhost", Header: Header{}},
+	}
+	resps, err := RunPipeline(context.Background(), reqs)
+	require.ErrorContains(t, err, "step 1:")
+	require.Empty(t, resps)
+}
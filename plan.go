@@ -0,0 +1,94 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultUserAgent is what net/http sends when no User-Agent is set.
+const defaultUserAgent = "Go-http-client/1.1"
+
+// RequestPlan describes exactly what executing a Request puts on the wire.
+type RequestPlan struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// RequestURI is the request target sent in the request line.
+	RequestURI  string      `json:"request_uri"`
+	URLEncoding URLEncoding `json:"url_encoding"`
+	// Header holds the effective headers, including the defaults added by
+	// net/http (Host, User-Agent, Content-Length, Accept-Encoding).
+	Header     http.Header   `json:"header"`
+	BodyLength int64         `json:"body_length"`
+	Transport  PlanTransport `json:"transport"`
+	Warnings   []string      `json:"warnings,omitempty"`
+}
+
+type PlanTransport struct {
+	InsecureSkipVerify bool          `json:"insecure_skip_verify"`
+	Timeout            time.Duration `json:"timeout"`
+	ConnectTimeout     time.Duration `json:"connect_timeout,omitempty"`
+}
+
+// Plan describes the request as ToHTTPRequest builds it: the body length
+// and Content-Type, multipart boundary included, are those sent. When the
+// request can't be built, e.g. because of a missing file, the body length
+// is estimated like WireSize does and a warning is added.
+func (r *Request) Plan() *RequestPlan {
+	p := &RequestPlan{
+		Method:      r.Method,
+		URL:         r.URL,
+		Header:      http.Header{},
+		URLEncoding: r.URLEncoding,
+		Transport:   PlanTransport{InsecureSkipVerify: r.SkipTLS, ConnectTimeout: r.ConnectTimeout},
+	}
+	header, host := r.HTTPHeader(), ""
+	_, p.BodyLength = r.WireSize()
+	if u, err := r.httpURL(); err != nil {
+		p.Warnings = append(p.Warnings, "invalid URL: "+err.Error())
+	} else {
+		p.URL = u.String()
+		p.RequestURI = u.RequestURI()
+		if u.Opaque != "" {
+			p.URL = u.Scheme + "://" + u.Host + p.RequestURI
+		}
+		p.Header.Set("Host", u.Host)
+
+		if hreq, err := r.ToHTTPRequest(context.Background()); err != nil {
+			p.Warnings = append(p.Warnings, "invalid request: "+err.Error())
+		} else {
+			header, host = hreq.Header, hreq.Host
+			if hreq.ContentLength >= 0 {
+				p.BodyLength = hreq.ContentLength
+			}
+		}
+	}
+
+	for k, vals := range header {
+		p.Header[k] = append([]string(nil), vals...)
+	}
+	if host != "" {
+		p.Header.Set("Host", host)
+	}
+	if p.Header.Get("User-Agent") == "" {
+		p.Header.Set("User-Agent", defaultUserAgent)
+	}
+	if p.BodyLength > 0 && !isChunked(r.Header) {
+		p.Header.Set("Content-Length", strconv.FormatInt(p.BodyLength, 10))
+	}
+	// The transport asks for gzip and transparently decodes it unless the
+	// caller set Accept-Encoding itself.
+	if p.Header.Get("Accept-Encoding") == "" && r.Method != http.MethodHead {
+		p.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	if r.Timeout != "" {
+		timeout, err := ParseDuration(r.Timeout)
+		if err != nil {
+			p.Warnings = append(p.Warnings, "invalid timeout "+strconv.Quote(r.Timeout))
+		}
+		p.Transport.Timeout = timeout
+	}
+	return p
+}
@@ -0,0 +1,83 @@
+package gcurl
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan(t *testing.T) {
+	req, err := Parse(`curl -k -m 1.5 -d 'name=sid' https://api.site.com/sloths`)
+	require.NoError(t, err)
+
+	require.Equal(t, &RequestPlan{
+		Method:     http.MethodPost,
+		URL:        "https://api.site.com/sloths",
+		RequestURI: "/sloths",
+		Header: http.Header{
+			"Host":            {"api.site.com"},
+			"User-Agent":      {"Go-http-client/1.1"},
+			"Content-Type":    {"application/x-www-form-urlencoded"},
+			"Content-Length":  {"8"},
+			"Accept-Encoding": {"gzip"},
+		},
+		BodyLength: 8,
+		Transport: PlanTransport{
+			InsecureSkipVerify: true,
+			Timeout:            1500 * time.Millisecond,
+		},
+	}, req.Plan())
+}
+
+func TestPlanMultipart(t *testing.T) {
+	req, err := Parse(`curl -F name=sid https://api.site.com/sloths`)
+	require.NoError(t, err)
+	require.NoError(t, req.SetFormBoundary("sloth-boundary"))
+
+	plan := req.Plan()
+	_, body := req.WireSize()
+	require.Equal(t, body, plan.BodyLength)
+	require.Equal(t, "multipart/form-data; boundary=sloth-boundary", plan.Header.Get("Content-Type"))
+	require.Equal(t, strconv.FormatInt(body, 10), plan.Header.Get("Content-Length"))
+	require.Empty(t, plan.Warnings)
+}
+
+func TestPlanFileBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sloth.bin")
+	require.NoError(t, os.WriteFile(path, []byte("three toed"), 0o644))
+
+	for _, cmd := range []string{
+		`curl --data-binary @` + path + ` https://api.site.com/sloths`,
+		`curl -T ` + path + ` https://api.site.com/sloths/`,
+	} {
+		req, err := Parse(cmd)
+		require.NoError(t, err)
+		plan := req.Plan()
+		require.EqualValues(t, 10, plan.BodyLength, cmd)
+		require.Equal(t, "10", plan.Header.Get("Content-Length"), cmd)
+		require.Empty(t, plan.Warnings, cmd)
+	}
+
+	req, err := Parse(`curl -T missing.bin https://api.site.com/sloths/`)
+	require.NoError(t, err)
+	plan := req.Plan()
+	require.Zero(t, plan.BodyLength)
+	require.Len(t, plan.Warnings, 1)
+}
+
+func TestPlanKeepsExplicitHeaders(t *testing.T) {
+	req, err := Parse(`curl -I -A slothy -m soon https://api.site.com`)
+	require.NoError(t, err)
+
+	plan := req.Plan()
+	require.Equal(t, http.Header{
+		"Host":       {"api.site.com"},
+		"User-Agent": {"slothy"},
+	}, plan.Header)
+	require.Equal(t, []string{`invalid timeout "soon"`}, plan.Warnings)
+}
@@ -0,0 +1,87 @@
+package gcurl
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyURL returns the proxy given with -x, completed like curl does: the
+// scheme defaults to http and the port to 1080. ProxyAuth is set as its
+// userinfo. It returns nil if the request has no proxy.
+func (r *Request) ProxyURL() (*url.URL, error) {
+	if r.Proxy == "" {
+		return nil, nil
+	}
+	raw := r.Proxy
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), "1080")
+	}
+	if r.ProxyAuth != "" {
+		user, pass, ok := strings.Cut(r.ProxyAuth, ":")
+		if ok {
+			u.User = url.UserPassword(user, pass)
+		} else {
+			u.User = url.User(user)
+		}
+	}
+	return u, nil
+}
+
+// UseProxy reports whether requests to host go through the proxy, that
+// is unless host matches NoProxy. Like curl, "*" matches every host and
+// other entries match the host and its subdomains.
+func (r *Request) UseProxy(host string) bool {
+	if r.Proxy == "" {
+		return false
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	for _, entry := range r.NoProxy {
+		entry = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(entry), "."))
+		switch {
+		case entry == "*":
+			return false
+		case entry == "":
+		case host == entry, strings.HasSuffix(host, "."+entry):
+			return false
+		}
+	}
+	return true
+}
+
+// ProxyFunc returns a function for http.Transport.Proxy applying the
+// request proxy settings, or nil if the request has no proxy.
+func (r *Request) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	proxy, err := r.ProxyURL()
+	if proxy == nil || err != nil {
+		return nil, err
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if !r.UseProxy(req.URL.Host) {
+			return nil, nil
+		}
+		return proxy, nil
+	}, nil
+}
+
+// parseNoProxy splits a --noproxy list.
+func parseNoProxy(list string) []string {
+	var res []string
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			res = append(res, entry)
+		}
+	}
+	return res
+}
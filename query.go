@@ -0,0 +1,32 @@
+package gcurl
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Query returns the parameters of the URL query string, including the
+// data of -G. Like url.ParseQuery, it returns the parameters it could
+// parse along with the first error.
+func (r *Request) Query() (url.Values, error) {
+	_, query, _ := splitQuery(r.URL)
+	return url.ParseQuery(query)
+}
+
+// SetQuery replaces the URL query string with the encoding of v, sorted
+// by name. The rest of the URL is kept as is, and an empty v removes the
+// query string.
+func (r *Request) SetQuery(v url.Values) {
+	base, _, frag := splitQuery(r.URL)
+	r.URL = appendQuery(base, v.Encode()) + frag
+}
+
+// splitQuery splits rawURL around its query string: base ends before the
+// "?" and frag starts with the "#".
+func splitQuery(rawURL string) (base, query, frag string) {
+	if i := strings.IndexByte(rawURL, '#'); i >= 0 {
+		rawURL, frag = rawURL[:i], rawURL[i:]
+	}
+	base, query, _ = strings.Cut(rawURL, "?")
+	return base, query, frag
+}
@@ -0,0 +1,58 @@
+package gcurl
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestQuery(t *testing.T) {
+	var tests = []struct {
+		name     string
+		given    string
+		expected url.Values
+	}{
+		{"url", `curl 'https://api.site.com/search?q=sloth&page=2&q=two+toed#top'`, url.Values{"q": {"sloth", "two toed"}, "page": {"2"}}},
+		{"get data", `curl -G -d 'q=sloth' --data-urlencode 'lang=en us' 'https://api.site.com/search?page=2'`, url.Values{"q": {"sloth"}, "lang": {"en us"}, "page": {"2"}}},
+		{"empty value", `curl 'https://api.site.com/search?q&page='`, url.Values{"q": {""}, "page": {""}}},
+		{"none", `curl https://api.site.com/search`, url.Values{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := Parse(tt.given)
+			require.NoError(t, err)
+			query, err := req.Query()
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestRequestQueryInvalid(t *testing.T) {
+	req, err := Parse(`curl 'https://api.site.com/search?q=sloth&bad=%zz'`)
+	require.NoError(t, err)
+	query, err := req.Query()
+	require.Error(t, err)
+	require.Equal(t, url.Values{"q": {"sloth"}}, query)
+}
+
+func TestRequestSetQuery(t *testing.T) {
+	req, err := Parse(`curl 'https://api.site.com/search?q=sloth&page=2#top'`)
+	require.NoError(t, err)
+
+	query, err := req.Query()
+	require.NoError(t, err)
+	query.Set("page", "3")
+	query.Add("lang", "en us")
+	req.SetQuery(query)
+	require.Equal(t, "https://api.site.com/search?lang=en+us&page=3&q=sloth#top", req.URL)
+
+	req.SetQuery(nil)
+	require.Equal(t, "https://api.site.com/search#top", req.URL)
+
+	req.SetQuery(url.Values{"q": {"sloth"}})
+	require.Equal(t, "https://api.site.com/search?q=sloth#top", req.URL)
+}
@@ -0,0 +1,42 @@
+package gcurl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// WithRandom draws the randomness of the executor from r instead of
+// crypto/rand: the multipart boundaries of forms that don't set one. A
+// seeded source such as math/rand.New(math/rand.NewSource(1)) makes runs
+// reproducible, e.g. for golden files.
+func WithRandom(r io.Reader) ExecOption {
+	return func(c *execConfig) { c.random = r }
+}
+
+// randomBoundary returns a multipart boundary read from r, shaped like
+// those of mime/multipart.
+func randomBoundary(r io.Reader) (string, error) {
+	var buf [30]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return "", fmt.Errorf("form boundary: %w", err)
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
+
+// withBoundary returns r, or a copy of it with a boundary drawn from the
+// configured source when r is a form without one.
+func (c *execConfig) withBoundary(r *Request) (*Request, error) {
+	if c.random == nil || len(r.Form) == 0 || r.FormBoundary() != "" {
+		return r, nil
+	}
+	boundary, err := randomBoundary(c.random)
+	if err != nil {
+		return nil, err
+	}
+	r = r.Clone()
+	if err := r.SetFormBoundary(boundary); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
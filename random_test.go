@@ -0,0 +1,41 @@
+package gcurl
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRandom(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(r.Header.Get("Content-Type") + "\n" + string(body)))
+	}))
+	defer srv.Close()
+
+	req, err := Parse("curl -F name=sid -F kind=sloth " + srv.URL)
+	require.NoError(t, err)
+	run := func(opts ...ExecOption) string {
+		resp, err := req.Execute(context.Background(), opts...)
+		require.NoError(t, err)
+		return resp.Text()
+	}
+
+	first := run(WithRandom(rand.New(rand.NewSource(1))))
+	require.Equal(t, first, run(WithRandom(rand.New(rand.NewSource(1)))))
+	require.NotEqual(t, first, run(WithRandom(rand.New(rand.NewSource(2)))))
+	require.Empty(t, req.FormBoundary(), "the request is left untouched")
+
+	// A boundary set on the request wins.
+	require.NoError(t, req.SetFormBoundary("sloth-boundary"))
+	require.True(t, strings.HasPrefix(run(WithRandom(rand.New(rand.NewSource(1)))), "multipart/form-data; boundary=sloth-boundary\n"))
+
+	_, err = randomBoundary(strings.NewReader("short"))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
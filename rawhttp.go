@@ -0,0 +1,70 @@
+package gcurl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ParseRawHTTP reads an HTTP/1.x request dump, such as those saved by
+// proxies or packet captures, into a Request. Origin-form targets are
+// resolved against the Host header over http, or https for port 443.
+func ParseRawHTTP(r io.Reader) (*Request, error) {
+	return parseRawHTTP(r, "")
+}
+
+func parseRawHTTP(r io.Reader, scheme string) (*Request, error) {
+	br := bufio.NewReader(r)
+	hreq, err := http.ReadRequest(br)
+	if err != nil {
+		return nil, fmt.Errorf("raw http: %w", err)
+	}
+
+	body, err := io.ReadAll(hreq.Body)
+	if err != nil {
+		return nil, fmt.Errorf("raw http: %w", err)
+	}
+	// Dumps often lack a Content-Length, in which case everything after
+	// the headers is the body.
+	if hreq.ContentLength <= 0 && len(hreq.TransferEncoding) == 0 {
+		rest, err := io.ReadAll(br)
+		if err != nil {
+			return nil, fmt.Errorf("raw http: %w", err)
+		}
+		body = append(body, rest...)
+	}
+
+	u := hreq.URL
+	if !u.IsAbs() {
+		u.Host = hreq.Host
+		u.Scheme = scheme
+		if u.Scheme == "" {
+			u.Scheme = "http"
+			if strings.HasSuffix(u.Host, ":443") {
+				u.Scheme = "https"
+			}
+		}
+	}
+
+	req := &Request{
+		Method: hreq.Method,
+		URL:    u.String(),
+		Header: Header{},
+		Body:   string(body),
+	}
+	for k, vals := range hreq.Header {
+		key := strings.ToLower(k)
+		if key == "content-length" {
+			continue
+		}
+		sep := ", "
+		if key == KeyCookie {
+			sep = "; "
+		}
+		req.Header[key] = strings.Join(vals, sep)
+	}
+	req.BodyKind = DetectBodyKind(req.Header[KeyContentType], req.Body)
+	return req, nil
+}
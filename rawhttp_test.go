@@ -0,0 +1,86 @@
+package gcurl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRawHTTP(t *testing.T) {
+	var tests = []struct {
+		name     string
+		given    string
+		expected *Request
+	}{
+		{
+			"origin form with body",
+			"POST /sloths?kind=two HTTP/1.1\r\n" +
+				"Host: api.site.com\r\n" +
+				"Content-Type: application/json\r\n" +
+				"Content-Length: 14\r\n" +
+				"Cookie: a=1\r\n" +
+				"Cookie: b=2\r\n" +
+				"\r\n" +
+				`{"name":"sid"}`,
+			&Request{
+				Method:   "POST",
+				URL:      "http://api.site.com/sloths?kind=two",
+				Header:   Header{"content-type": "application/json", "cookie": "a=1; b=2"},
+				Body:     `{"name":"sid"}`,
+				BodyKind: BodyJSON,
+			},
+		},
+		{
+			"bare newlines without content length",
+			"PUT /notes/1 HTTP/1.1\n" +
+				"Host: api.site.com:443\n" +
+				"\n" +
+				"hello",
+			&Request{
+				Method:   "PUT",
+				URL:      "https://api.site.com:443/notes/1",
+				Header:   Header{},
+				Body:     "hello",
+				BodyKind: BodyText,
+			},
+		},
+		{
+			"absolute form",
+			"GET https://api.site.com/sloths HTTP/1.1\r\n" +
+				"Host: api.site.com\r\n" +
+				"Accept: text/plain\r\n" +
+				"\r\n",
+			&Request{
+				Method: "GET",
+				URL:    "https://api.site.com/sloths",
+				Header: Header{"accept": "text/plain"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseRawHTTP(strings.NewReader(tt.given))
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseRawHTTPRoundTrip(t *testing.T) {
+	req, err := Parse(`curl -H 'X-Sloth: sid' -d 'name=sid' http://api.site.com/sloths`)
+	require.NoError(t, err)
+	wire, err := req.DumpWire()
+	require.NoError(t, err)
+
+	actual, err := ParseRawHTTP(strings.NewReader(string(wire)))
+	require.NoError(t, err)
+	delete(actual.Header, KeyUserAgent)
+	require.Equal(t, req, actual)
+}
+
+func TestParseRawHTTPInvalid(t *testing.T) {
+	_, err := ParseRawHTTP(strings.NewReader("not http\r\n\r\n"))
+	require.Error(t, err)
+}
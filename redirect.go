@@ -0,0 +1,75 @@
+package gcurl
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxRedirects matches curl's default --max-redirs.
+const DefaultMaxRedirects = 50
+
+// RedirectPolicy controls how redirects are followed, mirroring curl's
+// -L/--location, --max-redirs and --location-trusted options. Its
+// CheckRedirect method can be used as http.Client.CheckRedirect.
+type RedirectPolicy struct {
+	// Follow enables following redirects. When false the redirect
+	// response itself is returned, like curl without -L.
+	Follow bool
+	// MaxRedirects limits the number of hops. Zero uses
+	// DefaultMaxRedirects and a negative value means unlimited.
+	MaxRedirects int
+	// Trusted keeps Authorization and Cookie headers when redirected to
+	// another host, like --location-trusted. By default they are dropped.
+	Trusted bool
+	// AutoReferer sends the previous URL as Referer, like -e ";auto".
+	// Otherwise the Referer of the original request, if any, is kept.
+	AutoReferer bool
+	// OnRedirect, if set, is called for every hop before it is followed.
+	OnRedirect func(req *http.Request, via []*http.Request)
+}
+
+func (p *RedirectPolicy) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if !p.Follow {
+		return http.ErrUseLastResponse
+	}
+
+	max := p.MaxRedirects
+	if max == 0 {
+		max = DefaultMaxRedirects
+	}
+	if max > 0 && len(via) > max {
+		return fmt.Errorf("maximum (%d) redirects followed", max)
+	}
+
+	// curl only forwards credentials to the exact host:port of the
+	// original request, which is stricter than net/http's domain match.
+	first := via[0]
+	if req.URL.Host != first.URL.Host {
+		if p.Trusted {
+			for _, k := range []string{"Authorization", "Cookie"} {
+				if v := first.Header.Get(k); v != "" && req.Header.Get(k) == "" {
+					req.Header.Set(k, v)
+				}
+			}
+		} else {
+			req.Header.Del("Authorization")
+			req.Header.Del("Cookie")
+		}
+	}
+
+	// net/http sets Referer on its own terms: mirror curl instead.
+	if p.AutoReferer {
+		prev := *via[len(via)-1].URL
+		prev.User, prev.Fragment = nil, ""
+		req.Header.Set("Referer", prev.String())
+	} else if v := first.Header.Get("Referer"); v != "" {
+		req.Header.Set("Referer", v)
+	} else {
+		req.Header.Del("Referer")
+	}
+
+	if p.OnRedirect != nil {
+		p.OnRedirect(req, via)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectPolicy(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Auth", r.Header.Get("Authorization"))
+	}))
+	defer other.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/relative":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, other.URL+"/final", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.Header().Set("X-Auth", r.Header.Get("Authorization"))
+		}
+	}))
+	defer origin.Close()
+
+	get := func(p *RedirectPolicy, path string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, origin.URL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer sloth")
+		client := &http.Client{CheckRedirect: p.CheckRedirect}
+		return client.Do(req)
+	}
+
+	t.Run("not following", func(t *testing.T) {
+		resp, err := get(&RedirectPolicy{}, "/relative")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusFound, resp.StatusCode)
+	})
+
+	t.Run("relative redirect keeps credentials", func(t *testing.T) {
+		var hops int
+		p := &RedirectPolicy{Follow: true, OnRedirect: func(*http.Request, []*http.Request) { hops++ }}
+		resp, err := get(p, "/relative")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "Bearer sloth", resp.Header.Get("X-Auth"))
+		require.Equal(t, 1, hops)
+	})
+
+	t.Run("cross-host strips credentials", func(t *testing.T) {
+		resp, err := get(&RedirectPolicy{Follow: true}, "/away")
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Header.Get("X-Auth"))
+	})
+
+	t.Run("trusted keeps credentials", func(t *testing.T) {
+		resp, err := get(&RedirectPolicy{Follow: true, Trusted: true}, "/away")
+		require.NoError(t, err)
+		require.Equal(t, "Bearer sloth", resp.Header.Get("X-Auth"))
+	})
+
+	t.Run("max redirects", func(t *testing.T) {
+		_, err := get(&RedirectPolicy{Follow: true, MaxRedirects: 3}, "/loop")
+		require.ErrorContains(t, err, "maximum (3) redirects followed")
+	})
+}
+
+func TestParseRedirects(t *testing.T) {
+	var tests = []struct {
+		given    string
+		follow   bool
+		max      int
+		trusted  bool
+		expected string
+	}{
+		{`curl https://api.site.com`, false, 0, false, `curl https://api.site.com`},
+		{`curl -L https://api.site.com`, true, 0, false, `curl -L https://api.site.com`},
+		{`curl --location --max-redirs 5 https://api.site.com`, true, 5, false, `curl -L --max-redirs 5 https://api.site.com`},
+		{`curl --location-trusted https://api.site.com`, true, 0, true, `curl --location-trusted https://api.site.com`},
+		{`curl -L --max-redirs -1 https://api.site.com`, true, -1, false, `curl -L --max-redirs -1 https://api.site.com`},
+		{`curl -L --max-redirs 0 https://api.site.com`, false, 0, false, `curl https://api.site.com`},
+	}
+
+	for _, tt := range tests {
+		req, err := Parse(tt.given)
+		require.NoError(t, err, tt.given)
+		require.Equal(t, tt.follow, req.FollowRedirects, tt.given)
+		require.Equal(t, tt.max, req.MaxRedirects, tt.given)
+		require.Equal(t, tt.trusted, req.TrustRedirects, tt.given)
+		require.Equal(t, tt.expected, req.curl(), tt.given)
+	}
+
+	for _, given := range []string{
+		`curl --max-redirs many https://api.site.com`,
+		`curl --max-redirs -2 https://api.site.com`,
+	} {
+		_, err := Parse(given)
+		require.ErrorContains(t, err, "invalid --max-redirs", given)
+	}
+}
+
+func TestDoFollowsParsedRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/loop" {
+			http.Redirect(w, r, "/loop", http.StatusFound)
+			return
+		}
+		if r.URL.Path != "/final" {
+			http.Redirect(w, r, "/final", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+
+	resp, err := Do(context.Background(), "curl "+srv.URL+"/start")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	resp, err = Do(context.Background(), "curl -L "+srv.URL+"/start")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = Do(context.Background(), "curl -L --max-redirs 2 "+srv.URL+"/loop")
+	require.ErrorContains(t, err, "maximum (2) redirects followed")
+}
+
+func TestReferer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/final" {
+			http.Redirect(w, r, "/final", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Referer")))
+	}))
+	defer srv.Close()
+
+	var tests = []struct {
+		name    string
+		given   string
+		header  string
+		auto    bool
+		curl    string
+		hopping string
+	}{
+		{"none", `curl -L ` + srv.URL + `/start`, "", false, `curl -L ` + srv.URL + `/start`, ""},
+		{"fixed", `curl -L -e https://docs.site.com ` + srv.URL + `/start`, "https://docs.site.com", false, `curl -H 'referer: https://docs.site.com' -L ` + srv.URL + `/start`, "https://docs.site.com"},
+		{"auto", `curl -L --referer 'https://docs.site.com;auto' ` + srv.URL + `/start`, "https://docs.site.com", true, `curl -H 'referer: https://docs.site.com' -L -e ';auto' ` + srv.URL + `/start`, srv.URL + "/start"},
+		{"auto only", `curl -L -e ';auto' ` + srv.URL + `/start`, "", true, `curl -L -e ';auto' ` + srv.URL + `/start`, srv.URL + "/start"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := Parse(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.header, req.Header.Get("Referer"))
+			require.Equal(t, tt.auto, req.AutoReferer)
+			require.Equal(t, tt.curl, req.curl())
+
+			resp, err := req.Execute(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.hopping, resp.Text())
+		})
+	}
+}
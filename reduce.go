@@ -0,0 +1,115 @@
+package gcurl
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Reduce minimizes a request for bug reports: it repeatedly removes single
+// headers, cookies, query parameters and body fields, keeping each removal
+// for which probe still returns true (e.g. "the server still answers 500").
+// The input request is not modified. If probe rejects the request as
+// given, a copy of it is returned unchanged.
+func Reduce(req *Request, probe func(*Request) bool) *Request {
+	cur := req.Clone()
+	if !probe(cur.Clone()) {
+		return cur
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, candidate := range reductions(cur) {
+			if probe(candidate.Clone()) {
+				cur = candidate
+				changed = true
+				break
+			}
+		}
+	}
+	return cur
+}
+
+// reductions lists the requests obtained by removing one element of r.
+func reductions(r *Request) []*Request {
+	var res []*Request
+
+	for _, k := range sortedKeys(r.Header) {
+		c := r.Clone()
+		delete(c.Header, k)
+		res = append(res, c)
+	}
+
+	if cookie, ok := r.Header[KeyCookie]; ok {
+		parts := strings.Split(cookie, ";")
+		for i := range parts {
+			if len(parts) < 2 {
+				break
+			}
+			c := r.Clone()
+			c.Header[KeyCookie] = strings.TrimSpace(strings.Join(without(parts, i), ";"))
+			res = append(res, c)
+		}
+	}
+
+	if u, err := url.Parse(r.URL); err == nil && u.RawQuery != "" {
+		params := strings.Split(u.RawQuery, "&")
+		for i := range params {
+			c := r.Clone()
+			u.RawQuery = strings.Join(without(params, i), "&")
+			c.URL = u.String()
+			res = append(res, c)
+		}
+	}
+
+	if r.Body != "" {
+		for _, body := range bodyReductions(r.Body) {
+			c := r.Clone()
+			c.Body = body
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+func bodyReductions(body string) []string {
+	var res []string
+
+	data := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(body), &data); err == nil {
+		for _, k := range sortedMapKeys(data) {
+			v := data[k]
+			delete(data, k)
+			if body, err := encodeJSON(data); err == nil {
+				res = append(res, body)
+			}
+			data[k] = v
+		}
+		return res
+	}
+
+	fields := strings.Split(body, "&")
+	if len(fields) > 1 {
+		for i := range fields {
+			res = append(res, strings.Join(without(fields, i), "&"))
+		}
+		return res
+	}
+	return []string{""}
+}
+
+func without(s []string, i int) []string {
+	res := make([]string, 0, len(s)-1)
+	res = append(res, s[:i]...)
+	return append(res, s[i+1:]...)
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
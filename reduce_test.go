@@ -0,0 +1,52 @@
+package gcurl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReduce(t *testing.T) {
+	req, err := Parse(`curl -H 'Accept: */*' -H 'X-Debug: 1' -H 'Authorization: Bearer t' ` +
+		`-b 'session=abc; theme=dark; lang=en' ` +
+		`-H 'Content-Type: application/json' -d '{"name":"sid","kind":"two","age":3}' ` +
+		`'https://api.site.com/sloths?page=2&trace=on'`)
+	require.NoError(t, err)
+	original := req.Clone()
+
+	// The "bug" needs the session cookie, the X-Debug header, the trace
+	// parameter and the kind field.
+	probe := func(r *Request) bool {
+		return r.Header["x-debug"] == "1" &&
+			strings.Contains(r.Header[KeyCookie], "session=abc") &&
+			strings.Contains(r.URL, "trace=on") &&
+			strings.Contains(r.Body, `"kind":"two"`)
+	}
+
+	actual := Reduce(req, probe)
+	require.Equal(t, &Request{
+		Method:   "POST",
+		URL:      "https://api.site.com/sloths?trace=on",
+		Header:   Header{"x-debug": "1", "cookie": "session=abc"},
+		Body:     `{"kind":"two"}`,
+		BodyKind: BodyJSON,
+	}, actual)
+	require.Equal(t, original, req)
+}
+
+func TestReduceFormBody(t *testing.T) {
+	req := &Request{Method: "POST", URL: "https://api.site.com", Header: Header{}, Body: "a=1&b=2&c=3"}
+	actual := Reduce(req, func(r *Request) bool { return strings.Contains(r.Body, "b=2") })
+	require.Equal(t, "b=2", actual.Body)
+
+	actual = Reduce(req, func(r *Request) bool { return true })
+	require.Equal(t, "", actual.Body)
+}
+
+func TestReduceFailingProbe(t *testing.T) {
+	req := &Request{Method: "GET", URL: "https://api.site.com", Header: Header{"accept": "*/*"}}
+	actual := Reduce(req, func(r *Request) bool { return false })
+	require.Equal(t, req, actual)
+	require.NotSame(t, req, actual)
+}
@@ -0,0 +1,96 @@
+package gcurl
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultReproSnippetSize is used when NewRepro is given a zero size.
+const DefaultReproSnippetSize = 1024
+
+// Repro is a self-contained reproduction of a request and the response it
+// got, meant to be attached to bug reports.
+type Repro struct {
+	Command     string `json:"command"`
+	Status      string `json:"status"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	// Snippet is the beginning of the response body. Binary bodies are
+	// summarized instead.
+	Snippet string `json:"snippet,omitempty"`
+	// Size is the full body size; Truncated reports whether Snippet is
+	// shorter than the body.
+	Size      int64 `json:"size"`
+	Truncated bool  `json:"truncated,omitempty"`
+}
+
+// NewRepro builds the repro of req and its response, keeping at most
+// snippetSize bytes of the body (DefaultReproSnippetSize if zero). Secrets
+// are redacted from the command as by StringRedacted.
+func NewRepro(req *Request, resp *Response, snippetSize int) *Repro {
+	if snippetSize <= 0 {
+		snippetSize = DefaultReproSnippetSize
+	}
+	r := &Repro{
+		Command:     req.StringRedacted(),
+		Status:      resp.Status,
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.Size(),
+	}
+	if r.Status == "" {
+		r.Status = fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode))
+	}
+
+	body := resp.Bytes()
+	if len(body) > snippetSize {
+		body = trimPartialRune(body[:snippetSize])
+		r.Truncated = true
+	}
+	if !utf8.Valid(body) {
+		r.Snippet = fmt.Sprintf("[%d bytes of binary data]", r.Size)
+		r.Truncated = false
+		return r
+	}
+	r.Snippet = string(body)
+	return r
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence cut at the end of b.
+func trimPartialRune(b []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(b); i++ {
+		if utf8.RuneStart(b[len(b)-i]) {
+			if !utf8.FullRune(b[len(b)-i:]) {
+				return b[:len(b)-i]
+			}
+			break
+		}
+	}
+	return b
+}
+
+// Markdown renders the repro for an issue tracker.
+func (r *Repro) Markdown() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "```sh\n%s\n```\n\n", r.Command)
+	fmt.Fprintf(b, "Status: %s\n", r.Status)
+	if r.Snippet == "" {
+		return b.String()
+	}
+
+	b.WriteString("\nResponse")
+	var details []string
+	if r.ContentType != "" {
+		details = append(details, r.ContentType)
+	}
+	if r.Truncated {
+		details = append(details, fmt.Sprintf("first %d of %d bytes", len(r.Snippet), r.Size))
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(b, " (%s)", strings.Join(details, ", "))
+	}
+	fmt.Fprintf(b, ":\n\n```\n%s\n```\n", strings.TrimRight(r.Snippet, "\n"))
+	return b.String()
+}
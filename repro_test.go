@@ -0,0 +1,64 @@
+package gcurl
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRepro(t *testing.T) {
+	req, err := Parse(`curl -X DELETE https://api.site.com/sloths/4`)
+	require.NoError(t, err)
+
+	resp := newTestResponse("application/json", `{"error":"sloth is sleeping"}`)
+	resp.StatusCode, resp.Status = http.StatusConflict, "409 Conflict"
+
+	repro := NewRepro(req, resp, 0)
+	require.Equal(t, &Repro{
+		Command:     "curl -X DELETE https://api.site.com/sloths/4",
+		Status:      "409 Conflict",
+		StatusCode:  409,
+		ContentType: "application/json",
+		Snippet:     `{"error":"sloth is sleeping"}`,
+		Size:        29,
+	}, repro)
+
+	require.Equal(t, "```sh\ncurl -X DELETE https://api.site.com/sloths/4\n```\n\n"+
+		"Status: 409 Conflict\n\n"+
+		"Response (application/json):\n\n```\n{\"error\":\"sloth is sleeping\"}\n```\n", repro.Markdown())
+
+	data, err := json.Marshal(repro)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"status_code":409`)
+}
+
+func TestNewReproRedacted(t *testing.T) {
+	req, err := Parse(`curl -H 'Authorization: Bearer s3cr3t' https://api.site.com/sloths`)
+	require.NoError(t, err)
+
+	repro := NewRepro(req, newTestResponse("text/plain", "denied"), 0)
+	require.NotContains(t, repro.Command, "s3cr3t")
+	require.Contains(t, repro.Command, "REDACTED")
+	require.Equal(t, "Bearer s3cr3t", req.Header["authorization"])
+}
+
+func TestNewReproTruncation(t *testing.T) {
+	req := &Request{Method: "GET", URL: "https://api.site.com", Header: Header{}}
+
+	resp := newTestResponse("text/plain", strings.Repeat("é", 10))
+	repro := NewRepro(req, resp, 5)
+	require.Equal(t, "éé", repro.Snippet)
+	require.True(t, repro.Truncated)
+	require.Contains(t, repro.Markdown(), "Response (text/plain, first 4 of 20 bytes):")
+
+	resp = newTestResponse("image/png", "\x89PNG\r\n\x1a\n\x00\x00\xff")
+	repro = NewRepro(req, resp, 0)
+	require.Equal(t, "[11 bytes of binary data]", repro.Snippet)
+	require.False(t, repro.Truncated)
+
+	repro = NewRepro(req, newTestResponse("", ""), 0)
+	require.Equal(t, "```sh\ncurl https://api.site.com\n```\n\nStatus: 200 OK\n", repro.Markdown())
+}
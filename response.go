@@ -0,0 +1,64 @@
+package gcurl
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Response is an executed request's response with its body fully read.
+// The embedded Body can still be read once and is backed by memory.
+type Response struct {
+	*http.Response
+	// DryRun is set instead of a real response when executed with DryRun.
+	DryRun *DryRunReport
+
+	body []byte
+}
+
+func (r *Response) Bytes() []byte {
+	return r.body
+}
+
+func (r *Response) Text() string {
+	return string(r.body)
+}
+
+// JSON decodes the body into v.
+func (r *Response) JSON(v interface{}) error {
+	return json.Unmarshal(r.body, v)
+}
+
+// SaveTo writes the body to the file at path, creating or truncating it.
+func (r *Response) SaveTo(path string) error {
+	return os.WriteFile(path, r.body, 0o644)
+}
+
+// Size returns the body size in bytes, as received after decompression.
+func (r *Response) Size() int64 {
+	return int64(len(r.body))
+}
+
+// MediaType returns the body media type from Content-Type, falling back
+// to content sniffing.
+func (r *Response) MediaType() string {
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil {
+		return mt
+	}
+	mt, _, _ := mime.ParseMediaType(http.DetectContentType(r.body))
+	return mt
+}
+
+// Charset returns the lower-cased body charset declared in Content-Type,
+// falling back to content sniffing. It is empty when unknown.
+func (r *Response) Charset() string {
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && params["charset"] != "" {
+		return strings.ToLower(params["charset"])
+	}
+	if _, params, err := mime.ParseMediaType(http.DetectContentType(r.body)); err == nil {
+		return strings.ToLower(params["charset"])
+	}
+	return ""
+}
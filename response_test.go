@@ -0,0 +1,48 @@
+package gcurl
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestResponse(contentType, body string) *Response {
+	h := http.Header{}
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	return &Response{Response: &http.Response{StatusCode: http.StatusOK, Header: h}, body: []byte(body)}
+}
+
+func TestResponseBodyHelpers(t *testing.T) {
+	resp := newTestResponse("application/json; charset=UTF-8", `{"name":"sid"}`)
+
+	require.Equal(t, `{"name":"sid"}`, resp.Text())
+	require.Equal(t, []byte(`{"name":"sid"}`), resp.Bytes())
+	require.Equal(t, int64(14), resp.Size())
+	require.Equal(t, "application/json", resp.MediaType())
+	require.Equal(t, "utf-8", resp.Charset())
+
+	var v struct{ Name string }
+	require.NoError(t, resp.JSON(&v))
+	require.Equal(t, "sid", v.Name)
+
+	path := filepath.Join(t.TempDir(), "body.json")
+	require.NoError(t, resp.SaveTo(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, resp.Bytes(), data)
+}
+
+func TestResponseSniffing(t *testing.T) {
+	resp := newTestResponse("", "<html><body>sloth</body></html>")
+	require.Equal(t, "text/html", resp.MediaType())
+	require.Equal(t, "utf-8", resp.Charset())
+
+	resp = newTestResponse("", "\x00\x01\x02")
+	require.Equal(t, "application/octet-stream", resp.MediaType())
+	require.Equal(t, "", resp.Charset())
+}
@@ -0,0 +1,102 @@
+package gcurl
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// RewriteRule redirects matching requests elsewhere before they are sent,
+// typically to replay production traffic against staging. Empty fields
+// neither match nor change anything.
+type RewriteRule struct {
+	// Host matches the request host case-insensitively, including the
+	// port if Host has one.
+	Host string
+	// PathPrefix matches whole leading path segments: "/v1" matches
+	// "/v1" and "/v1/sloths" but not "/v10".
+	PathPrefix string
+
+	// ToHost replaces the host, and the port if ToHost has one.
+	ToHost string
+	// ToPathPrefix replaces the matched PathPrefix, or is prepended to
+	// the path without one.
+	ToPathPrefix string
+	// ToScheme replaces the scheme, e.g. "http" to reach a staging server
+	// without TLS.
+	ToScheme string
+}
+
+// WithRewrite applies the first matching rule to each request before it
+// is sent. The Host header follows the new host unless it was set
+// explicitly. Redirects are followed as received.
+func WithRewrite(rules ...RewriteRule) ExecOption {
+	return func(c *execConfig) { c.rewrites = append(c.rewrites, rules...) }
+}
+
+// WithHostRewrite sends the requests for host to toHost, e.g.
+// WithHostRewrite("api.prod.com", "api.staging.local:8443").
+func WithHostRewrite(host, toHost string) ExecOption {
+	return WithRewrite(RewriteRule{Host: host, ToHost: toHost})
+}
+
+// rewrite applies the first rule matching u, reporting whether one did.
+func rewrite(rules []RewriteRule, u *url.URL) bool {
+	for _, r := range rules {
+		if r.apply(u) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r RewriteRule) apply(u *url.URL) bool {
+	if !matchHost(r.Host, u) {
+		return false
+	}
+	path := u.EscapedPath()
+	prefix := strings.TrimSuffix(r.PathPrefix, "/")
+	if r.PathPrefix != "" && path != prefix && !strings.HasPrefix(path, prefix+"/") {
+		return false
+	}
+
+	if r.ToScheme != "" {
+		u.Scheme = r.ToScheme
+	}
+	if r.ToHost != "" {
+		port := u.Port()
+		u.Host = r.ToHost
+		if !hasPort(r.ToHost) && port != "" {
+			u.Host = net.JoinHostPort(strings.Trim(r.ToHost, "[]"), port)
+		}
+	}
+	if r.PathPrefix != "" || r.ToPathPrefix != "" {
+		rest := strings.TrimPrefix(path, prefix)
+		path = strings.TrimSuffix(r.ToPathPrefix, "/") + rest
+		if path == "" {
+			path = "/"
+		}
+		if p, err := url.PathUnescape(path); err == nil {
+			u.Path, u.RawPath = p, path
+		}
+	}
+	return true
+}
+
+// matchHost reports whether u is for host, compared case-insensitively and
+// including the port if host has one. An empty host matches any URL.
+func matchHost(host string, u *url.URL) bool {
+	if host == "" {
+		return true
+	}
+	h := u.Hostname()
+	if hasPort(host) {
+		h = u.Host
+	}
+	return strings.EqualFold(strings.Trim(h, "[]"), strings.Trim(host, "[]"))
+}
+
+func hasPort(host string) bool {
+	_, port, err := net.SplitHostPort(host)
+	return err == nil && port != ""
+}
@@ -0,0 +1,73 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteRule(t *testing.T) {
+	var tests = []struct {
+		name     string
+		rule     RewriteRule
+		given    string
+		expected string
+	}{
+		{"host", RewriteRule{Host: "api.prod.com", ToHost: "api.staging.local:8443"}, "https://API.prod.com/sloths?page=2", "https://api.staging.local:8443/sloths?page=2"},
+		{"host keeps port", RewriteRule{Host: "api.prod.com", ToHost: "api.staging.local"}, "https://api.prod.com:9443/sloths", "https://api.staging.local:9443/sloths"},
+		{"host with port", RewriteRule{Host: "api.prod.com:9443", ToHost: "localhost:8080"}, "https://api.prod.com:9443/", "https://localhost:8080/"},
+		{"host with other port", RewriteRule{Host: "api.prod.com:9443", ToHost: "localhost:8080"}, "https://api.prod.com/", ""},
+		{"other host", RewriteRule{Host: "api.prod.com", ToHost: "localhost"}, "https://cdn.prod.com/", ""},
+		{"ipv6", RewriteRule{Host: "api.prod.com", ToHost: "[::1]"}, "https://api.prod.com:8443/", "https://[::1]:8443/"},
+		{"path prefix", RewriteRule{PathPrefix: "/v1", ToPathPrefix: "/api/v2"}, "https://api.prod.com/v1/sloths/4", "https://api.prod.com/api/v2/sloths/4"},
+		{"path prefix whole segments", RewriteRule{PathPrefix: "/v1/", ToPathPrefix: "/v2"}, "https://api.prod.com/v10/sloths", ""},
+		{"path prefix exact", RewriteRule{PathPrefix: "/v1", ToPathPrefix: "/"}, "https://api.prod.com/v1", "https://api.prod.com/"},
+		{"path prepended", RewriteRule{ToPathPrefix: "/staging"}, "https://api.prod.com/sloths%2F4", "https://api.prod.com/staging/sloths%2F4"},
+		{"scheme downgrade", RewriteRule{Host: "api.prod.com", ToScheme: "http"}, "https://api.prod.com/sloths", "http://api.prod.com/sloths"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.given)
+			require.NoError(t, err)
+			ok := rewrite([]RewriteRule{tt.rule}, u)
+			if tt.expected == "" {
+				require.False(t, ok)
+				require.Equal(t, tt.given, u.String())
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tt.expected, u.String())
+		})
+	}
+}
+
+func TestDoWithRewrite(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + r.URL.RequestURI()))
+	}))
+	defer srv.Close()
+	staging := strings.TrimPrefix(srv.URL, "http://")
+
+	resp, err := Do(context.Background(), "curl 'https://api.prod.com/v1/sloths?page=2'",
+		WithRewrite(
+			RewriteRule{Host: "cdn.prod.com", ToHost: "localhost:1"},
+			RewriteRule{Host: "api.prod.com", ToHost: staging, ToScheme: "http", PathPrefix: "/v1", ToPathPrefix: "/staging/v1"},
+		))
+	require.NoError(t, err)
+	require.Equal(t, staging+"/staging/v1/sloths?page=2", resp.Text())
+
+	resp, err = Do(context.Background(), "curl http://api.prod.com/sloths", WithHostRewrite("api.prod.com", staging))
+	require.NoError(t, err)
+	require.Equal(t, staging+"/sloths", resp.Text())
+
+	resp, err = Do(context.Background(), "curl -H 'Host: api.prod.com' http://api.prod.com/sloths", WithHostRewrite("api.prod.com", staging))
+	require.NoError(t, err)
+	require.Equal(t, "api.prod.com/sloths", resp.Text())
+}
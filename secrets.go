@@ -0,0 +1,216 @@
+package gcurl
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SensitiveHeaders lists the header name patterns whose values are treated
+// as secrets, in HeaderFilter pattern syntax.
+var SensitiveHeaders = []string{
+	"authorization", "proxy-authorization", "cookie",
+	"x-api-key", "x-auth-token", "x-access-token", "x-csrf-token", "*-secret",
+}
+
+// SensitiveParams lists the query, form and JSON field names whose values
+// are treated as secrets, matched case-insensitively.
+var SensitiveParams = []string{
+	"token", "access_token", "refresh_token", "id_token", "api_key", "apikey",
+	"key", "password", "passwd", "secret", "client_secret", "signature", "sig",
+}
+
+// Tokenize replaces the secrets of a request with stable placeholders
+// ({{TOKEN_1}}, {{TOKEN_2}}, ...) so it can be shared publicly. It returns
+// the tokenized copy and the placeholder to secret mapping that Hydrate
+// uses to restore it. Identical secrets share a placeholder.
+func Tokenize(req *Request) (*Request, map[string]string) {
+	values := map[string]string{}
+	placeholders := map[string]string{}
+	res := replaceSecrets(req, func(secret string) string {
+		if p, ok := placeholders[secret]; ok {
+			return p
+		}
+		p := fmt.Sprintf("{{TOKEN_%d}}", len(placeholders)+1)
+		placeholders[secret] = p
+		values[p] = secret
+		return p
+	})
+	return res, values
+}
+
+// Hydrate returns a copy of req with the placeholders produced by Tokenize
+// replaced by their values.
+func Hydrate(req *Request, values map[string]string) *Request {
+	pairs := make([]string, 0, 2*len(values))
+	for p, v := range values {
+		pairs = append(pairs, p, v)
+	}
+	r := strings.NewReplacer(pairs...)
+
+	res := req.Clone()
+	res.URL = r.Replace(res.URL)
+	res.Body = r.Replace(res.Body)
+	res.ProxyAuth = r.Replace(res.ProxyAuth)
+	if a := res.Auth; a != nil {
+		derived := res.derivedHeader()
+		a.Password, a.Token = r.Replace(a.Password), r.Replace(a.Token)
+		if derived {
+			res.Header[KeyAuthorization], _ = a.header()
+		}
+	}
+	for k, v := range res.Header {
+		res.Header[k] = r.Replace(v)
+	}
+	for _, vals := range res.MultiHeader {
+		for i, v := range vals {
+			vals[i] = r.Replace(v)
+		}
+	}
+	for i, f := range res.Form {
+		res.Form[i].Value = r.Replace(f.Value)
+	}
+	return res
+}
+
+// replaceSecrets returns a copy of req where every secret value is
+// substituted by replace. Secrets are visited in a deterministic order:
+// headers by name, the credentials, the proxy password, the URL password
+// and query, then the body and form fields.
+func replaceSecrets(req *Request, replace func(secret string) string) *Request {
+	res := req.Clone()
+	sensitive := &HeaderFilter{Allow: SensitiveHeaders}
+
+	// An Authorization header derived from the credentials is derived
+	// again from the replaced ones.
+	derived := res.derivedHeader()
+	for _, k := range sortedKeys(res.Header) {
+		if !sensitive.Keep(k) || k == KeyAuthorization && derived {
+			continue
+		}
+		ck := http.CanonicalHeaderKey(k)
+		for i, v := range res.MultiHeader[ck] {
+			res.MultiHeader[ck][i] = replaceHeaderSecret(k, v, replace)
+		}
+		res.Header[k] = replaceHeaderSecret(k, res.Header[k], replace)
+	}
+
+	if a := res.Auth; a != nil {
+		if a.Password != "" {
+			a.Password = replace(a.Password)
+		}
+		if a.Token != "" {
+			a.Token = replace(a.Token)
+		}
+		if derived {
+			res.Header[KeyAuthorization], _ = a.header()
+		}
+	}
+	if user, pass, ok := strings.Cut(res.ProxyAuth, ":"); ok && pass != "" {
+		res.ProxyAuth = user + ":" + replace(pass)
+	}
+	res.URL = replaceURLPassword(res.URL, replace)
+	if base, query, ok := strings.Cut(res.URL, "?"); ok {
+		res.URL = base + "?" + replaceParams(query, "&", isSensitiveParam, replace)
+	}
+
+	if res.Body != "" {
+		var data interface{}
+		if err := json.Unmarshal([]byte(res.Body), &data); err == nil {
+			if replaceJSONSecrets(data, replace) {
+				if body, err := encodeJSON(data); err == nil {
+					res.Body = body
+				}
+			}
+		} else if strings.Contains(res.Body, "=") {
+			res.Body = replaceParams(res.Body, "&", isSensitiveParam, replace)
+		}
+	}
+	for i, f := range res.Form {
+		if f.Value != "" && isSensitiveParam(f.Name) {
+			res.Form[i].Value = replace(f.Value)
+		}
+	}
+	return res
+}
+
+func replaceHeaderSecret(k, v string, replace func(string) string) string {
+	switch k {
+	case KeyCookie:
+		return replaceParams(v, "; ", func(string) bool { return true }, replace)
+	case KeyAuthorization, "proxy-authorization":
+		// Keep the scheme so the command still reads naturally.
+		if scheme, cred, ok := strings.Cut(v, " "); ok {
+			return scheme + " " + replace(cred)
+		}
+	}
+	return replace(v)
+}
+
+// replaceURLPassword replaces the password of the userinfo in rawURL.
+func replaceURLPassword(rawURL string, replace func(string) string) string {
+	prefix, info, rest, ok := splitUserinfo(rawURL)
+	if !ok {
+		return rawURL
+	}
+	user, pass, ok := strings.Cut(info, ":")
+	if !ok || pass == "" {
+		return rawURL
+	}
+	return prefix + user + ":" + replace(pass) + "@" + rest
+}
+
+// replaceParams replaces the values of the sensitive name=value pairs of a
+// sep-separated list, leaving the rest of the text untouched.
+func replaceParams(s, sep string, sensitive func(string) bool, replace func(string) string) string {
+	parts := strings.Split(s, strings.TrimSpace(sep))
+	for i, part := range parts {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			continue
+		}
+		key := strings.TrimSpace(name)
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if sensitive(key) {
+			parts[i] = name + "=" + replace(val)
+		}
+	}
+	return strings.Join(parts, strings.TrimSpace(sep))
+}
+
+func replaceJSONSecrets(data interface{}, replace func(string) string) bool {
+	changed := false
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedMapKeys(v) {
+			val := v[k]
+			if s, ok := val.(string); ok && s != "" && isSensitiveParam(k) {
+				v[k] = replace(s)
+				changed = true
+			} else if replaceJSONSecrets(val, replace) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			if replaceJSONSecrets(val, replace) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func isSensitiveParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range SensitiveParams {
+		if name == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,200 @@
+package gcurl
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PreSendHook modifies an outgoing request right before it is sent,
+// typically to sign it or add credentials.
+type PreSendHook interface {
+	BeforeSend(req *http.Request) error
+}
+
+// PreSendHookFunc adapts a function to the PreSendHook interface.
+type PreSendHookFunc func(req *http.Request) error
+
+func (f PreSendHookFunc) BeforeSend(req *http.Request) error {
+	return f(req)
+}
+
+// WithPreSendHook runs h on every request before it is sent. Hooks run in
+// the order they are given.
+func WithPreSendHook(h PreSendHook) ExecOption {
+	return func(c *execConfig) { c.hooks = append(c.hooks, h) }
+}
+
+// HMACSigner signs the request body with an HMAC, in the style of webhook
+// signatures: <Header>: <Prefix><hex digest>.
+type HMACSigner struct {
+	Key []byte
+	// Header receives the signature, X-Signature if empty.
+	Header string
+	// Prefix is prepended to the hex digest, e.g. "sha256=".
+	Prefix string
+	// Hash is the digest function, sha256.New if nil.
+	Hash func() hash.Hash
+}
+
+func (s *HMACSigner) BeforeSend(req *http.Request) error {
+	body, err := requestBody(req)
+	if err != nil {
+		return err
+	}
+
+	hf := s.Hash
+	if hf == nil {
+		hf = sha256.New
+	}
+	mac := hmac.New(hf, s.Key)
+	mac.Write(body)
+
+	header := s.Header
+	if header == "" {
+		header = "X-Signature"
+	}
+	req.Header.Set(header, s.Prefix+hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+// MessageSigner signs requests per RFC 9421 HTTP Message Signatures,
+// setting the Signature-Input and Signature headers.
+type MessageSigner struct {
+	KeyID string
+	// Algorithm is "hmac-sha256" (using Key) or "ed25519" (using PrivateKey).
+	Algorithm  string
+	Key        []byte
+	PrivateKey ed25519.PrivateKey
+	// Components lists the covered components, e.g. "@method",
+	// "@authority", "@path" or lower-cased header names. Defaults to
+	// "@method", "@authority" and "@request-target".
+	Components []string
+	// Label names the signature, sig1 if empty.
+	Label string
+	// IncludeAlg adds the alg parameter to the signature parameters.
+	IncludeAlg bool
+	// Now returns the creation time, time.Now if nil.
+	Now func() time.Time
+}
+
+func (s *MessageSigner) BeforeSend(req *http.Request) error {
+	components := s.Components
+	if len(components) == 0 {
+		components = []string{"@method", "@authority", "@request-target"}
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	label := s.Label
+	if label == "" {
+		label = "sig1"
+	}
+
+	quoted := make([]string, len(components))
+	for i, c := range components {
+		quoted[i] = strconv.Quote(c)
+	}
+	params := "(" + strings.Join(quoted, " ") + ");created=" + strconv.FormatInt(now().Unix(), 10)
+	if s.KeyID != "" {
+		params += ";keyid=" + strconv.Quote(s.KeyID)
+	}
+	if s.IncludeAlg {
+		params += ";alg=" + strconv.Quote(s.Algorithm)
+	}
+
+	base := &strings.Builder{}
+	for _, c := range components {
+		val, err := signatureComponent(req, c)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(base, "%q: %s\n", c, val)
+	}
+	fmt.Fprintf(base, "%q: %s", "@signature-params", params)
+
+	var sig []byte
+	switch s.Algorithm {
+	case "hmac-sha256":
+		mac := hmac.New(sha256.New, s.Key)
+		mac.Write([]byte(base.String()))
+		sig = mac.Sum(nil)
+	case "ed25519":
+		sig = ed25519.Sign(s.PrivateKey, []byte(base.String()))
+	default:
+		return fmt.Errorf("message signature: unsupported algorithm %q", s.Algorithm)
+	}
+
+	req.Header.Set("Signature-Input", label+"="+params)
+	req.Header.Set("Signature", label+"=:"+base64.StdEncoding.EncodeToString(sig)+":")
+	return nil
+}
+
+func signatureComponent(req *http.Request, name string) (string, error) {
+	u := req.URL
+	switch name {
+	case "@method":
+		return strings.ToUpper(req.Method), nil
+	case "@target-uri":
+		return u.String(), nil
+	case "@authority":
+		host := req.Host
+		if host == "" {
+			host = u.Host
+		}
+		host = strings.ToLower(host)
+		if u.Scheme == "https" {
+			host = strings.TrimSuffix(host, ":443")
+		} else {
+			host = strings.TrimSuffix(host, ":80")
+		}
+		return host, nil
+	case "@scheme":
+		return strings.ToLower(u.Scheme), nil
+	case "@request-target":
+		return u.RequestURI(), nil
+	case "@path":
+		return u.EscapedPath(), nil
+	case "@query":
+		return "?" + u.RawQuery, nil
+	}
+	if strings.HasPrefix(name, "@") {
+		return "", fmt.Errorf("message signature: unsupported component %q", name)
+	}
+
+	vals := req.Header.Values(name)
+	if len(vals) == 0 {
+		return "", fmt.Errorf("message signature: missing header %q", name)
+	}
+	for i, v := range vals {
+		vals[i] = strings.TrimSpace(v)
+	}
+	return strings.Join(vals, ", "), nil
+}
+
+// requestBody returns a copy of the request body without consuming it.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be re-read")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
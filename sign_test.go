@@ -0,0 +1,74 @@
+package gcurl
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageSignerHMAC(t *testing.T) {
+	// Test vector from RFC 9421, appendix B.2.5.
+	key, err := base64.StdEncoding.DecodeString("uzvJfB4u3N0Jy4T7NZ75MDVcr8zSTInedJtkgcu46YW4XByzNJjxBdtjUkdJPBtbmHhIDi6pcl8jsasjlTMtDQ==")
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/foo?param=Value&Pet=dog", strings.NewReader(`{"hello": "world"}`))
+	require.NoError(t, err)
+	req.Header.Set("Date", "Tue, 20 Apr 2021 02:07:55 GMT")
+	req.Header.Set("Content-Type", "application/json")
+
+	s := &MessageSigner{
+		KeyID:      "test-shared-secret",
+		Algorithm:  "hmac-sha256",
+		Key:        key,
+		Components: []string{"date", "@authority", "content-type"},
+		Label:      "sig-b25",
+		Now:        func() time.Time { return time.Unix(1618884473, 0) },
+	}
+	require.NoError(t, s.BeforeSend(req))
+	require.Equal(t, `sig-b25=("date" "@authority" "content-type");created=1618884473;keyid="test-shared-secret"`, req.Header.Get("Signature-Input"))
+	require.Equal(t, "sig-b25=:pxcQw6G3AjtMBQjwo8XzkZf/bws5LelbaMk5rGIGtE8=:", req.Header.Get("Signature"))
+}
+
+func TestMessageSignerEd25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.site.com:443/sloths?kind=two", nil)
+	require.NoError(t, err)
+
+	s := &MessageSigner{Algorithm: "ed25519", PrivateKey: priv, IncludeAlg: true, Now: func() time.Time { return time.Unix(1, 0) }}
+	require.NoError(t, s.BeforeSend(req))
+
+	params := `("@method" "@authority" "@request-target");created=1;alg="ed25519"`
+	require.Equal(t, "sig1="+params, req.Header.Get("Signature-Input"))
+
+	base := "\"@method\": GET\n\"@authority\": api.site.com\n\"@request-target\": /sloths?kind=two\n\"@signature-params\": " + params
+	sig := strings.TrimSuffix(strings.TrimPrefix(req.Header.Get("Signature"), "sig1=:"), ":")
+	raw, err := base64.StdEncoding.DecodeString(sig)
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(pub, []byte(base), raw))
+
+	s.Components = []string{"x-missing"}
+	require.ErrorContains(t, s.BeforeSend(req), `missing header "x-missing"`)
+}
+
+func TestHMACSignerHook(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Hub-Signature-256")
+	}))
+	defer srv.Close()
+
+	signer := &HMACSigner{Key: []byte("It's a Secret to Everybody"), Header: "X-Hub-Signature-256", Prefix: "sha256="}
+	_, err := Do(context.Background(), `curl -d 'Hello, World!' `+srv.URL, WithPreSendHook(signer))
+	require.NoError(t, err)
+	// Example from GitHub's webhook validation documentation.
+	require.Equal(t, "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", got)
+}
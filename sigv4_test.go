@@ -0,0 +1,91 @@
+package gcurl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestSigV4(t *testing.T) {
+	var tests = []struct {
+		name     string
+		given    string
+		expected SigV4Params
+		ok       bool
+	}{
+		{"full", `curl --aws-sigv4 'aws:amz:us-east-1:s3' -u AKIA:s3cr3t https://bucket.example.com`, SigV4Params{"aws", "amz", "us-east-1", "s3"}, true},
+		{"provider2 defaulted", `curl --aws-sigv4 aws -u AKIA:s3cr3t https://api.example.com`, SigV4Params{Provider1: "aws", Provider2: "aws"}, true},
+		{"from host", `curl --aws-sigv4 aws:amz -u AKIA:s3cr3t https://ec2.eu-west-1.amazonaws.com/`, SigV4Params{"aws", "amz", "eu-west-1", "ec2"}, true},
+		{"region given", `curl --aws-sigv4 aws:amz:us-east-2 -u AKIA:s3cr3t https://ec2.eu-west-1.amazonaws.com/`, SigV4Params{"aws", "amz", "us-east-2", "ec2"}, true},
+		{"basic", `curl -u AKIA:s3cr3t https://api.example.com`, SigV4Params{}, false},
+		{"no auth", `curl https://api.example.com`, SigV4Params{}, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := Parse(tt.given)
+			require.NoError(t, err)
+			params, ok := req.SigV4()
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.expected, params)
+		})
+	}
+}
+
+func TestParseInvalidSigV4(t *testing.T) {
+	for _, given := range []string{"", ":amz", "aws:amz:us-east-1:s3:extra", "aws:a.mz"} {
+		_, err := Parse(`curl --aws-sigv4 '` + given + `' -u AKIA:s3cr3t https://api.example.com`)
+		require.True(t, errors.Is(err, ErrInvalidSigV4), given)
+	}
+}
+
+func TestSignSigV4(t *testing.T) {
+	// The example of the AWS Signature Version 4 documentation.
+	hreq, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Version=2010-05-08&Action=ListUsers", nil)
+	require.NoError(t, err)
+	hreq.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	params := SigV4Params{Provider1: "aws", Provider2: "amz", Region: "us-east-1", Service: "iam"}
+	require.NoError(t, signSigV4(hreq, params, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", now))
+	require.Equal(t, "20150830T123600Z", hreq.Header.Get("X-Amz-Date"))
+	require.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-date, "+
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7", hreq.Header.Get("Authorization"))
+}
+
+func TestSigV4Canonical(t *testing.T) {
+	require.Equal(t, "/", sigV4Path("", true))
+	require.Equal(t, "/a%2520b/c", sigV4Path("/a b/c", true))
+	require.Equal(t, "/a%20b/c", sigV4Path("/a b/c", false))
+	require.Equal(t, "a=1&a=2&b=x%20y&c=", sigV4Query("b=x+y&a=2&c&a=1"))
+}
+
+func TestExecuteSigV4(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	clock := &stubClock{now: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	req, err := Parse(`curl --aws-sigv4 'aws:amz:us-east-1:s3' -u AKIA:s3cr3t -d sloth ` + srv.URL + `/bucket/key`)
+	require.NoError(t, err)
+
+	_, err = req.Execute(context.Background(), WithSigV4Signing(), WithClock(clock))
+	require.NoError(t, err)
+	require.Equal(t, "20240501T100000Z", got.Get("X-Amz-Date"))
+	require.Equal(t, "068f5cfbbee6b482d1849e2f19cacf0151408f314c3bdcb025aeccb10a8ef69e", got.Get("X-Amz-Content-Sha256"))
+	require.Regexp(t, `^AWS4-HMAC-SHA256 Credential=AKIA/20240501/us-east-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`, got.Get("Authorization"))
+
+	// Without the option, the basic credentials of -u aren't sent either.
+	_, err = req.Execute(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, got.Get("Authorization"))
+}
@@ -0,0 +1,151 @@
+package gcurl
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// Stats aggregates usage statistics over a parsed corpus. Requests are
+// deduplicated by their Fingerprint.
+type Stats struct {
+	Commands int
+	Errors   int
+	Hosts    map[string]int
+	Methods  map[string]int
+	Headers  map[string]int
+	Flags    map[string]int
+	Requests map[string]*RequestStat
+}
+
+// RequestStat counts the occurrences of a unique request.
+type RequestStat struct {
+	Fingerprint string
+	Request     *Request
+	Count       int
+	FirstLine   int
+}
+
+// Count is a key with its number of occurrences.
+type Count struct {
+	Key string
+	N   int
+}
+
+func NewStats() *Stats {
+	return &Stats{
+		Hosts:    map[string]int{},
+		Methods:  map[string]int{},
+		Headers:  map[string]int{},
+		Flags:    map[string]int{},
+		Requests: map[string]*RequestStat{},
+	}
+}
+
+// Aggregate drains results into a new Stats.
+func Aggregate(results <-chan BulkResult) *Stats {
+	s := NewStats()
+	for res := range results {
+		s.Add(res)
+	}
+	return s
+}
+
+func (s *Stats) Add(res BulkResult) {
+	s.Commands++
+	for _, flag := range commandFlags(res.Command) {
+		s.Flags[flag]++
+	}
+	if res.Err != nil || res.Request == nil {
+		s.Errors++
+		return
+	}
+
+	req := res.Request
+	s.Methods[req.Method]++
+	if u, err := url.Parse(req.URL); err == nil {
+		s.Hosts[u.Host]++
+	}
+	for k := range req.Header {
+		s.Headers[k]++
+	}
+
+	fp := req.Fingerprint()
+	stat, ok := s.Requests[fp]
+	if !ok {
+		stat = &RequestStat{Fingerprint: fp, Request: req, FirstLine: res.Line}
+		s.Requests[fp] = stat
+	}
+	stat.Count++
+	if res.Line < stat.FirstLine {
+		stat.FirstLine = res.Line
+	}
+}
+
+// Unique returns the number of distinct requests seen.
+func (s *Stats) Unique() int {
+	return len(s.Requests)
+}
+
+// Duplicates returns the requests seen more than once, most frequent first.
+func (s *Stats) Duplicates() []*RequestStat {
+	var dups []*RequestStat
+	for _, stat := range s.Requests {
+		if stat.Count > 1 {
+			dups = append(dups, stat)
+		}
+	}
+	sort.Slice(dups, func(i, j int) bool {
+		if dups[i].Count != dups[j].Count {
+			return dups[i].Count > dups[j].Count
+		}
+		return dups[i].Fingerprint < dups[j].Fingerprint
+	})
+	return dups
+}
+
+func (s *Stats) TopHosts(n int) []Count   { return TopCounts(s.Hosts, n) }
+func (s *Stats) TopMethods(n int) []Count { return TopCounts(s.Methods, n) }
+func (s *Stats) TopHeaders(n int) []Count { return TopCounts(s.Headers, n) }
+func (s *Stats) TopFlags(n int) []Count   { return TopCounts(s.Flags, n) }
+
+// TopCounts returns the n most frequent keys, ties broken alphabetically.
+// A non-positive n returns all keys.
+func TopCounts(counts map[string]int, n int) []Count {
+	res := make([]Count, 0, len(counts))
+	for k, v := range counts {
+		res = append(res, Count{Key: k, N: v})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].N != res[j].N {
+			return res[i].N > res[j].N
+		}
+		return res[i].Key < res[j].Key
+	})
+	if n > 0 && len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
+// commandFlags lists the options used in a curl command line.
+func commandFlags(cmd string) []string {
+	args, err := shellwords.Parse(cmd)
+	if err != nil {
+		return nil
+	}
+
+	var flags []string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			flag, _, _ := strings.Cut(arg, "=")
+			flags = append(flags, flag)
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			flags = append(flags, arg[:2])
+		}
+	}
+	return flags
+}
@@ -0,0 +1,36 @@
+package gcurl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	given := strings.Join([]string{
+		"curl -s https://api.site.com/sloths",
+		"curl -s https://api.site.com/sloths",
+		"curl -XDELETE -H 'X-Sloth: sid' https://api.site.com/sloths/4",
+		"curl --compressed https://cdn.site.com/logo.png",
+		"curl -H 'broken https://api.site.com",
+	}, "\n")
+
+	results := (&BulkParser{Workers: 2}).ParseReader(context.Background(), strings.NewReader(given))
+	s := Aggregate(results)
+
+	require.Equal(t, 5, s.Commands)
+	require.Equal(t, 1, s.Errors)
+	require.Equal(t, 3, s.Unique())
+	require.Equal(t, []Count{{"api.site.com", 3}, {"cdn.site.com", 1}}, s.TopHosts(0))
+	require.Equal(t, []Count{{"GET", 3}}, s.TopMethods(1))
+	require.Equal(t, []Count{{"accept-encoding", 1}, {"x-sloth", 1}}, s.TopHeaders(0))
+	require.Equal(t, []Count{{"-s", 2}, {"--compressed", 1}, {"-H", 1}, {"-X", 1}}, s.TopFlags(0))
+
+	dups := s.Duplicates()
+	require.Len(t, dups, 1)
+	require.Equal(t, 2, dups[0].Count)
+	require.Equal(t, 1, dups[0].FirstLine)
+	require.Equal(t, "https://api.site.com/sloths", dups[0].Request.URL)
+}
@@ -0,0 +1,26 @@
+package gcurl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedFlag is returned by strict parsers for curl options they
+// have no handling for.
+var ErrUnsupportedFlag = errors.New("unsupported curl option")
+
+// WithStrict makes the parser fail with ErrUnsupportedFlag, listing the
+// offending options, instead of ignoring options it doesn't understand.
+// Options registered with RegisterFlag or WithFlagHandler are supported.
+func WithStrict() ParseOption {
+	return func(p *Parser) { p.strict = true }
+}
+
+// checkUnknownFlags enforces strict parsing on the collected flags.
+func (p *Parser) checkUnknownFlags(flags []string) error {
+	if !p.strict || len(flags) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedFlag, strings.Join(flags, ", "))
+}
@@ -0,0 +1,29 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStrict(t *testing.T) {
+	p := NewParser(WithStrict(), WithFlagHandler("--fail", FlagHandler{
+		Handle: func(req *Request, value string) error { return nil },
+	}))
+
+	_, err := p.Parse(`curl -s --fail -H 'Accept: */*' --verbose https://api.site.com`)
+	require.ErrorIs(t, err, ErrUnsupportedFlag)
+	require.ErrorContains(t, err, "unsupported curl option: -s, --verbose")
+
+	req, err := p.Parse(`curl --fail -k -H 'Accept: */*' https://api.site.com`)
+	require.NoError(t, err)
+	require.True(t, req.SkipTLS)
+
+	req, err = p.Parse(`curl -kL https://api.site.com`)
+	require.NoError(t, err)
+	require.True(t, req.SkipTLS)
+	require.True(t, req.FollowRedirects)
+
+	_, err = Parse(`curl -s --verbose https://api.site.com`)
+	require.NoError(t, err)
+}
@@ -0,0 +1,63 @@
+package gcurl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses curl's decimal seconds, as taken by --max-time and
+// --connect-timeout, e.g. "30" or "0.5".
+func ParseDuration(s string) (time.Duration, error) {
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if secs < 0 {
+		return 0, fmt.Errorf("negative duration")
+	}
+	if math.IsNaN(secs) || secs > math.MaxInt64/float64(time.Second) {
+		return 0, fmt.Errorf("duration %q out of range", s)
+	}
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+// FormatDuration formats d in curl's decimal seconds, the reverse of
+// ParseDuration.
+func FormatDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// ParseSize parses a curl byte size, as taken by --limit-rate or
+// --max-filesize: an integer optionally followed by a B, K, M, G or T
+// unit, in any case, with binary multiples (1K is 1024 bytes).
+func ParseSize(s string) (int64, error) {
+	num, shift := s, 0
+	if i := len(s) - 1; i > 0 {
+		switch s[i] {
+		case 'b', 'B':
+			num = s[:i]
+		case 'k', 'K':
+			num, shift = s[:i], 10
+		case 'm', 'M':
+			num, shift = s[:i], 20
+		case 'g', 'G':
+			num, shift = s[:i], 30
+		case 't', 'T':
+			num, shift = s[:i], 40
+		}
+	}
+	if strings.HasPrefix(num, "+") {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	if n > math.MaxInt64>>shift {
+		return 0, fmt.Errorf("size %q out of range", s)
+	}
+	return n << shift, nil
+}
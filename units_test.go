@@ -0,0 +1,68 @@
+package gcurl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	var tests = []struct {
+		given    string
+		expected time.Duration
+		err      bool
+	}{
+		{"30", 30 * time.Second, false},
+		{"0.5", 500 * time.Millisecond, false},
+		{"0", 0, false},
+		{"-1", 0, true},
+		{"NaN", 0, true},
+		{"1e300", 0, true},
+		{"10s", 0, true},
+	}
+
+	for _, tt := range tests {
+		actual, err := ParseDuration(tt.given)
+		if tt.err {
+			require.Error(t, err, tt.given)
+			continue
+		}
+		require.NoError(t, err, tt.given)
+		require.Equal(t, tt.expected, actual, tt.given)
+		back, err := ParseDuration(FormatDuration(actual))
+		require.NoError(t, err)
+		require.Equal(t, actual, back)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	var tests = []struct {
+		given    string
+		expected int64
+		err      bool
+	}{
+		{"500", 500, false},
+		{"500b", 500, false},
+		{"500k", 500 << 10, false},
+		{"1M", 1 << 20, false},
+		{"2G", 2 << 30, false},
+		{"1t", 1 << 40, false},
+		{"k", 0, true},
+		{"1.5M", 0, true},
+		{"-1K", 0, true},
+		{"+1", 0, true},
+		{"1X", 0, true},
+		{"9999999999T", 0, true},
+	}
+
+	for _, tt := range tests {
+		actual, err := ParseSize(tt.given)
+		if tt.err {
+			require.Error(t, err, tt.given)
+			continue
+		}
+		require.NoError(t, err, tt.given)
+		require.Equal(t, tt.expected, actual, tt.given)
+	}
+}
@@ -0,0 +1,49 @@
+package gcurl
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// uploadURL appends the base name of file to rawURL when its path is
+// empty or ends with a slash, as curl -T does. Standard input ("-")
+// has no name to append.
+func uploadURL(rawURL, file string) string {
+	if file == "-" || rawURL == "" {
+		return rawURL
+	}
+	prefix, host, rest, ok := splitURLHost(rawURL)
+	if !ok {
+		// Scheme-less URLs, which curl sends over HTTP.
+		end := len(rawURL)
+		if i := strings.IndexAny(rawURL, "/?#"); i >= 0 {
+			end = i
+		}
+		host, rest = rawURL[:end], rawURL[end:]
+	}
+	p, tail := rest, ""
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		p, tail = rest[:i], rest[i:]
+	}
+	if p != "" && !strings.HasSuffix(p, "/") {
+		return rawURL
+	}
+	if p == "" {
+		p = "/"
+	}
+	name := url.PathEscape(path.Base(filepath.ToSlash(file)))
+	return prefix + host + p + name + tail
+}
+
+// readBodyFile reads the file of -T or of a --data-binary @file, "-"
+// being standard input.
+func readBodyFile(file string) ([]byte, error) {
+	if file == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(file)
+}
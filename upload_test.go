@@ -0,0 +1,86 @@
+package gcurl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUploadFile(t *testing.T) {
+	var tests = []struct {
+		name   string
+		given  string
+		method string
+		url    string
+		file   string
+	}{
+		{"named target", `curl -T notes.txt https://api.site.com/files/sid.txt`, http.MethodPut, "https://api.site.com/files/sid.txt", "notes.txt"},
+		{"directory target", `curl --upload-file ./docs/notes.txt https://api.site.com/files/`, http.MethodPut, "https://api.site.com/files/notes.txt", "./docs/notes.txt"},
+		{"no path", `curl -T notes.txt https://api.site.com`, http.MethodPut, "https://api.site.com/notes.txt", "notes.txt"},
+		{"query kept", `curl -T 'my notes.txt' 'https://api.site.com/files/?v=2'`, http.MethodPut, "https://api.site.com/files/my%20notes.txt?v=2", "my notes.txt"},
+		{"stdin", `curl -T - https://api.site.com/files/`, http.MethodPut, "https://api.site.com/files/", "-"},
+		{"explicit method", `curl -X POST -T notes.txt https://api.site.com/files/sid.txt`, http.MethodPost, "https://api.site.com/files/sid.txt", "notes.txt"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, info, err := ParseWithInfo(tt.given)
+			require.NoError(t, err)
+			require.Equal(t, tt.method, req.Method)
+			require.Equal(t, tt.url, req.URL)
+			require.Equal(t, tt.file, req.UploadFile)
+			require.Equal(t, BodyBinary, req.BodyKind)
+			require.Empty(t, req.Header)
+			if tt.method == http.MethodPut {
+				require.Equal(t, MethodReasonUpload, info.MethodReason)
+			}
+
+			again, err := Parse(req.curl())
+			require.NoError(t, err)
+			require.Equal(t, req, again)
+		})
+	}
+}
+
+func TestParseUploadFileConflicts(t *testing.T) {
+	for _, given := range []string{
+		`curl -T notes.txt -d a=1 https://api.site.com/files/`,
+		`curl -T notes.txt -F a=1 https://api.site.com/files/`,
+		`curl -I -T notes.txt https://api.site.com/files/`,
+	} {
+		_, err := Parse(given)
+		require.ErrorIs(t, err, ErrMethodConflict, given)
+	}
+}
+
+func TestUploadFileHTTPRequest(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(file, []byte("sloths sleep"), 0o644))
+
+	req, err := Parse("curl -T " + file + " https://api.site.com/files/")
+	require.NoError(t, err)
+
+	hreq, err := req.ToHTTPRequest(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPut, hreq.Method)
+	require.Equal(t, "/files/notes.txt", hreq.URL.Path)
+	body, err := io.ReadAll(hreq.Body)
+	require.NoError(t, err)
+	require.Equal(t, "sloths sleep", string(body))
+
+	_, bodyBytes := req.WireSize()
+	require.Equal(t, int64(12), bodyBytes)
+}
+
+func TestUploadURL(t *testing.T) {
+	require.Equal(t, "api.site.com/files/notes.txt", uploadURL("api.site.com/files/", "notes.txt"))
+	require.Equal(t, "api.site.com/notes.txt", uploadURL("api.site.com", "notes.txt"))
+	require.Equal(t, "https://sid@api.site.com/notes.txt#top", uploadURL("https://sid@api.site.com#top", "/tmp/notes.txt"))
+	require.Equal(t, "", uploadURL("", "notes.txt"))
+}
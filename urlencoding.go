@@ -0,0 +1,171 @@
+package gcurl
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// URLEncoding selects how the percent-encoding of the URL path and query
+// is sent.
+type URLEncoding int
+
+const (
+	// URLEncodingDefault follows net/url: valid escapes are sent as
+	// written, other characters needing it are encoded.
+	URLEncodingDefault URLEncoding = iota
+	// URLEncodingPreserve sends the path and query exactly as written,
+	// even invalid or unusual escapes, as security testing requires.
+	// Only bytes that can't appear in a request line (controls, spaces and
+	// non-ASCII) are encoded.
+	URLEncodingPreserve
+	// URLEncodingNormalize applies RFC 3986 normalization: escaped
+	// unreserved characters are decoded, hex digits upper-cased and
+	// invalid escapes encoded.
+	URLEncodingNormalize
+)
+
+var urlEncodingNames = []string{"default", "preserve", "normalize"}
+
+func (e URLEncoding) String() string {
+	if e < 0 || int(e) >= len(urlEncodingNames) {
+		return fmt.Sprintf("URLEncoding(%d)", int(e))
+	}
+	return urlEncodingNames[e]
+}
+
+func (e URLEncoding) MarshalText() ([]byte, error) {
+	if e < 0 || int(e) >= len(urlEncodingNames) {
+		return nil, fmt.Errorf("invalid URL encoding %d", int(e))
+	}
+	return []byte(e.String()), nil
+}
+
+func (e *URLEncoding) UnmarshalText(text []byte) error {
+	for i, name := range urlEncodingNames {
+		if string(text) == name {
+			*e = URLEncoding(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid URL encoding %q", text)
+}
+
+// WithURLEncoding sets the URLEncoding of parsed requests.
+func WithURLEncoding(e URLEncoding) ParseOption {
+	return func(p *Parser) { p.urlEncoding = e }
+}
+
+// httpURL parses the request URL for sending, applying URLEncoding.
+func (r *Request) httpURL() (*url.URL, error) {
+	target, err := asciiURL(r.URL)
+	if err != nil {
+		return nil, err
+	}
+	if r.URLEncoding == URLEncodingDefault {
+		return url.Parse(target)
+	}
+
+	prefix, host, rest, ok := splitURLHost(target)
+	if !ok {
+		return url.Parse(target)
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	path, query, hasQuery := strings.Cut(rest, "?")
+
+	if r.URLEncoding == URLEncodingNormalize {
+		target = prefix + host + normalizeEscapes(path, "?#") + "?" + normalizeEscapes(query, "#")
+		if !hasQuery {
+			target = strings.TrimSuffix(target, "?")
+		}
+		return url.Parse(target)
+	}
+
+	u, err := url.Parse(prefix + host)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		path = "/"
+	}
+	// An opaque value is sent verbatim as the request target, but one
+	// starting with // would be taken for a network path.
+	if !strings.HasPrefix(path, "//") {
+		u.Opaque = escapeRequestTarget(path)
+	} else if u.Path, err = url.PathUnescape(path); err == nil {
+		u.RawPath = path
+	} else {
+		u.Path = path
+	}
+	u.RawQuery = escapeRequestTarget(query)
+	u.ForceQuery = hasQuery && query == ""
+	return u, nil
+}
+
+// escapeRequestTarget encodes the bytes that can't appear in a request
+// line.
+func escapeRequestTarget(s string) string {
+	return escapeBytes(s, func(c byte) bool { return c <= ' ' || c >= 0x7f })
+}
+
+// normalizeEscapes normalizes the percent-encoding of a path or query,
+// also encoding the characters forbidden in URLs and extra.
+func normalizeEscapes(s, extra string) string {
+	b := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			v := unhex(s[i+1])<<4 | unhex(s[i+2])
+			if isUnreserved(v) {
+				b.WriteByte(v)
+			} else {
+				writeEscaped(b, v)
+			}
+			i += 2
+		case c == '%', c <= ' ', c >= 0x7f, strings.IndexByte(`"<>\^`+"`{|}"+extra, c) >= 0:
+			writeEscaped(b, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func escapeBytes(s string, escape func(byte) bool) string {
+	b := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; escape(c) {
+			writeEscaped(b, c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func writeEscaped(b *strings.Builder, c byte) {
+	const hex = "0123456789ABCDEF"
+	b.WriteByte('%')
+	b.WriteByte(hex[c>>4])
+	b.WriteByte(hex[c&15])
+}
+
+func isUnreserved(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	}
+	return c - '0'
+}
@@ -0,0 +1,62 @@
+package gcurl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLEncoding(t *testing.T) {
+	var tests = []struct {
+		name     string
+		url      string
+		encoding URLEncoding
+		target   string
+	}{
+		{"default keeps valid escapes", "https://api.site.com/a%2Fb%7e?q=%2f", URLEncodingDefault, "/a%2Fb%7e?q=%2f"},
+		{"default encodes spaces", "https://api.site.com/a b?q=1", URLEncodingDefault, "/a%20b?q=1"},
+		{"preserve", "https://api.site.com/a%2Fb%7e/..%2f?q=%zz&x=%", URLEncodingPreserve, "/a%2Fb%7e/..%2f?q=%zz&x=%"},
+		{"preserve request line bytes", "https://api.site.com/a b/é?q=\x01", URLEncodingPreserve, "/a%20b/%C3%A9?q=%01"},
+		{"preserve empty path", "https://api.site.com?q=1", URLEncodingPreserve, "/?q=1"},
+		{"preserve empty query", "https://api.site.com/a?", URLEncodingPreserve, "/a?"},
+		{"preserve network path", "https://api.site.com//a%2Fb", URLEncodingPreserve, "//a%2Fb"},
+		{"normalize", "https://api.site.com/%7e%41b%2f%zz?q=%2f%61 b", URLEncodingNormalize, "/~Ab%2F%25zz?q=%2Fa%20b"},
+		{"fragment dropped", "https://api.site.com/a%7e#frag", URLEncodingPreserve, "/a%7e"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := &Request{Method: "GET", URL: tt.url, Header: Header{}, URLEncoding: tt.encoding}
+
+			plan := req.Plan()
+			require.Empty(t, plan.Warnings)
+			require.Equal(t, tt.target, plan.RequestURI)
+			require.Equal(t, tt.encoding, plan.URLEncoding)
+
+			wire, err := req.DumpWire()
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(wire), "GET "+tt.target+" HTTP/1.1\r\nHost: api.site.com\r\n"), string(wire))
+			headers, _ := req.WireSize()
+			require.Equal(t, int64(len(wire)), headers)
+		})
+	}
+}
+
+func TestParseWithURLEncoding(t *testing.T) {
+	req, err := NewParser(WithURLEncoding(URLEncodingPreserve)).Parse(`curl 'https://api.site.com/..%2f..%2fetc/passwd'`)
+	require.NoError(t, err)
+	require.Equal(t, URLEncodingPreserve, req.URLEncoding)
+	require.Equal(t, "https://api.site.com/..%2f..%2fetc/passwd", req.Plan().URL)
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"url_encoding":"preserve"`)
+	decoded := &Request{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Equal(t, req, decoded)
+
+	require.Error(t, json.Unmarshal([]byte(`{"url_encoding":"raw"}`), decoded))
+}
@@ -0,0 +1,83 @@
+package gcurl
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// URLMatcher decides which command arguments are the target URL.
+type URLMatcher interface {
+	MatchURL(arg string) bool
+}
+
+// URLMatcherFunc adapts a function to a URLMatcher.
+type URLMatcherFunc func(arg string) bool
+
+func (f URLMatcherFunc) MatchURL(arg string) bool {
+	return f(arg)
+}
+
+var (
+	// PermissiveURLMatcher accepts anything starting with http:// or
+	// https://. It is the default.
+	PermissiveURLMatcher URLMatcher = URLMatcherFunc(isURL)
+	// StrictURLMatcher accepts http and https URLs that parse per RFC 3986
+	// with a valid host, including IPv6 literals, and port.
+	StrictURLMatcher URLMatcher = URLMatcherFunc(isStrictURL)
+)
+
+// WithURLMatcher sets how the target URL is recognized among the command
+// arguments.
+func WithURLMatcher(m URLMatcher) ParseOption {
+	return func(p *Parser) { p.urlMatcher = m }
+}
+
+func (p *Parser) matchURL(arg string) bool {
+	if p.urlMatcher == nil {
+		return isURL(arg)
+	}
+	return p.urlMatcher.MatchURL(arg)
+}
+
+var urlRe = regexp.MustCompile(`^https?://.*$`)
+
+func isURL(u string) bool {
+	return urlRe.MatchString(u)
+}
+
+func isStrictURL(arg string) bool {
+	if strings.ContainsAny(arg, " \t\r\n") {
+		return false
+	}
+	u, err := url.Parse(arg)
+	if err != nil || u.Opaque != "" {
+		return false
+	}
+	if s := strings.ToLower(u.Scheme); s != "http" && s != "https" {
+		return false
+	}
+
+	host := u.Hostname()
+	switch {
+	case host == "":
+		return false
+	case strings.HasPrefix(u.Host, "["):
+		if net.ParseIP(host) == nil || !strings.Contains(host, ":") {
+			return false
+		}
+	case strings.ContainsAny(host, "[]:"):
+		return false
+	}
+	if port := u.Port(); port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil || n > 65535 {
+			return false
+		}
+	} else if strings.HasSuffix(u.Host, ":") {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package gcurl
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// URLPolicy restricts the schemes and ports a request may target, for
+// services that accept curl commands from untrusted users.
+type URLPolicy struct {
+	// Schemes lists the allowed schemes, e.g. "https". Empty allows any.
+	Schemes []string
+	// Ports lists the allowed ports. URLs without an explicit port use
+	// the scheme's default one. Empty allows any.
+	Ports []int
+}
+
+// PolicyError reports a URL rejected by a URLPolicy.
+type PolicyError struct {
+	URL    string
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("url %q not allowed: %s", e.URL, e.Reason)
+}
+
+// Check returns a *PolicyError if rawURL is not allowed by the policy.
+func (p *URLPolicy) Check(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return &PolicyError{URL: rawURL, Reason: err.Error()}
+	}
+	return p.check(u, rawURL)
+}
+
+func (p *URLPolicy) check(u *url.URL, rawURL string) error {
+	scheme := strings.ToLower(u.Scheme)
+	if len(p.Schemes) > 0 && !containsFold(p.Schemes, scheme) {
+		return &PolicyError{URL: rawURL, Reason: fmt.Sprintf("scheme %q", scheme)}
+	}
+
+	if len(p.Ports) == 0 {
+		return nil
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPorts[scheme]
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return &PolicyError{URL: rawURL, Reason: fmt.Sprintf("port %q", port)}
+	}
+	for _, allowed := range p.Ports {
+		if n == allowed {
+			return nil
+		}
+	}
+	return &PolicyError{URL: rawURL, Reason: fmt.Sprintf("port %d", n)}
+}
+
+// checkProxy checks the proxy of req, as completed by ProxyURL, since it
+// receives the connections of the request.
+func (p *URLPolicy) checkProxy(req *Request) error {
+	u, err := req.ProxyURL()
+	if err != nil {
+		return &PolicyError{URL: req.Proxy, Reason: err.Error()}
+	}
+	if u == nil {
+		return nil
+	}
+	return p.check(u, req.Proxy)
+}
+
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithURLPolicy rejects commands whose URL or proxy is not allowed by
+// policy.
+func WithURLPolicy(policy *URLPolicy) ParseOption {
+	return func(p *Parser) { p.urlPolicy = policy }
+}
+
+// WithExecURLPolicy checks the request URL, its proxy and every redirect
+// target against policy before sending.
+func WithExecURLPolicy(policy *URLPolicy) ExecOption {
+	return func(c *execConfig) { c.urlPolicy = policy }
+}
+
+// checkRedirect wraps next so that followed redirect targets are checked
+// too.
+func (p *URLPolicy) checkRedirect(next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if err := next(req, via); err != nil {
+			return err
+		}
+		return p.check(req.URL, req.URL.String())
+	}
+}
@@ -0,0 +1,109 @@
+package gcurl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLPolicy(t *testing.T) {
+	policy := &URLPolicy{Schemes: []string{"HTTPS"}, Ports: []int{443, 8443}}
+
+	var tests = []struct {
+		url    string
+		reason string
+	}{
+		{"https://api.site.com/sloths", ""},
+		{"https://api.site.com:8443/sloths", ""},
+		{"HTTPS://api.site.com:443", ""},
+		{"http://api.site.com/sloths", `scheme "http"`},
+		{"https://api.site.com:22/", "port 22"},
+		{"ftp://api.site.com/", `scheme "ftp"`},
+	}
+
+	for _, tt := range tests {
+		err := policy.Check(tt.url)
+		if tt.reason == "" {
+			require.NoError(t, err, tt.url)
+			continue
+		}
+		var perr *PolicyError
+		require.ErrorAs(t, err, &perr, tt.url)
+		require.Equal(t, tt.reason, perr.Reason)
+		require.Equal(t, tt.url, perr.URL)
+	}
+
+	require.NoError(t, (&URLPolicy{}).Check("http://localhost:1"))
+}
+
+func TestParseWithURLPolicy(t *testing.T) {
+	p := NewParser(WithURLPolicy(&URLPolicy{Schemes: []string{"https"}}))
+
+	_, err := p.Parse(`curl https://api.site.com/sloths`)
+	require.NoError(t, err)
+
+	_, err = p.Parse(`curl http://api.site.com/sloths`)
+	var perr *PolicyError
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "http://api.site.com/sloths", perr.URL)
+
+	_, err = p.Parse(`curl -x https://proxy.site.com https://api.site.com/sloths`)
+	require.NoError(t, err)
+
+	_, err = p.Parse(`curl -x internal.corp:3128 https://api.site.com/sloths`)
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "internal.corp:3128", perr.URL)
+	require.Equal(t, `scheme "http"`, perr.Reason)
+}
+
+func TestExecURLPolicyProxy(t *testing.T) {
+	var proxied bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = true
+	}))
+	defer proxy.Close()
+
+	policy := WithExecURLPolicy(&URLPolicy{Schemes: []string{"http"}, Ports: []int{80}})
+	_, err := Do(context.Background(), "curl -x "+proxy.URL+" http://api.site.com/sloths", policy)
+	var perr *PolicyError
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, proxy.URL, perr.URL)
+	require.False(t, proxied)
+
+	_, err = Do(context.Background(), "curl -x "+proxy.URL+" http://api.site.com/sloths")
+	require.NoError(t, err)
+	require.True(t, proxied)
+}
+
+func TestExecURLPolicy(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer other.Close()
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL, http.StatusFound)
+	}))
+	defer origin.Close()
+
+	u, err := url.Parse(origin.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	policy := WithExecURLPolicy(&URLPolicy{Ports: []int{port}})
+
+	_, err = Do(ctx, "curl "+other.URL, policy)
+	var perr *PolicyError
+	require.ErrorAs(t, err, &perr)
+
+	_, err = Do(ctx, "curl "+origin.URL, policy)
+	require.NoError(t, err)
+
+	_, err = Do(ctx, "curl "+origin.URL, policy, WithRedirectPolicy(&RedirectPolicy{Follow: true}))
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, other.URL, perr.URL)
+}
@@ -0,0 +1,103 @@
+package gcurl
+
+import (
+	"net/url"
+	"strings"
+)
+
+// WebDAV methods of RFC 4918 and the CalDAV/versioning extensions.
+const (
+	MethodPropfind   = "PROPFIND"
+	MethodProppatch  = "PROPPATCH"
+	MethodMkcol      = "MKCOL"
+	MethodCopy       = "COPY"
+	MethodMove       = "MOVE"
+	MethodLock       = "LOCK"
+	MethodUnlock     = "UNLOCK"
+	MethodReport     = "REPORT"
+	MethodMkcalendar = "MKCALENDAR"
+)
+
+// WebDAV header map keys.
+const (
+	KeyDepth       = "depth"
+	KeyDestination = "destination"
+	KeyOverwrite   = "overwrite"
+)
+
+// Depth is the value of the WebDAV Depth header.
+type Depth string
+
+const (
+	Depth0        Depth = "0"
+	Depth1        Depth = "1"
+	DepthInfinity Depth = "infinity"
+)
+
+// IsWebDAV reports whether the request uses a WebDAV method or header.
+func (r *Request) IsWebDAV() bool {
+	switch strings.ToUpper(r.Method) {
+	case MethodPropfind, MethodProppatch, MethodMkcol, MethodCopy, MethodMove,
+		MethodLock, MethodUnlock, MethodReport, MethodMkcalendar:
+		return true
+	}
+	for _, k := range []string{KeyDepth, KeyDestination, KeyOverwrite} {
+		if _, ok := r.Header[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Depth returns the Depth header. ok is false if it is missing or is not
+// one of 0, 1 and infinity.
+func (r *Request) Depth() (d Depth, ok bool) {
+	switch d := Depth(strings.ToLower(strings.TrimSpace(r.Header[KeyDepth]))); d {
+	case Depth0, Depth1, DepthInfinity:
+		return d, true
+	}
+	return "", false
+}
+
+func (r *Request) SetDepth(d Depth) {
+	r.Header[KeyDepth] = string(d)
+}
+
+// Destination returns the Destination header of COPY and MOVE requests
+// as an absolute URL, resolving paths sent by some clients against the
+// request URL.
+func (r *Request) Destination() (string, bool) {
+	dest := strings.TrimSpace(r.Header[KeyDestination])
+	if dest == "" {
+		return "", false
+	}
+	d, err := url.Parse(dest)
+	if err != nil {
+		return "", false
+	}
+	if d.IsAbs() {
+		return dest, true
+	}
+	base, err := url.Parse(r.URL)
+	if err != nil {
+		return "", false
+	}
+	return base.ResolveReference(d).String(), true
+}
+
+func (r *Request) SetDestination(dest string) {
+	r.Header[KeyDestination] = dest
+}
+
+// Overwrite reports whether a COPY or MOVE may replace an existing
+// resource. Per RFC 4918 that is the case unless Overwrite is "F".
+func (r *Request) Overwrite() bool {
+	return !strings.EqualFold(strings.TrimSpace(r.Header[KeyOverwrite]), "F")
+}
+
+func (r *Request) SetOverwrite(overwrite bool) {
+	r.Header[KeyOverwrite] = "T"
+	if !overwrite {
+		r.Header[KeyOverwrite] = "F"
+	}
+}
@@ -0,0 +1,50 @@
+package gcurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebDAV(t *testing.T) {
+	req, err := Parse(`curl -X MOVE -H 'Destination: /files/new.txt' -H 'Overwrite: F' -H 'Depth: Infinity' https://dav.sloths.com/files/old.txt`)
+	require.NoError(t, err)
+	require.True(t, req.IsWebDAV())
+
+	depth, ok := req.Depth()
+	require.True(t, ok)
+	require.Equal(t, DepthInfinity, depth)
+	dest, ok := req.Destination()
+	require.True(t, ok)
+	require.Equal(t, "https://dav.sloths.com/files/new.txt", dest)
+	require.False(t, req.Overwrite())
+
+	req.SetDepth(Depth0)
+	req.SetDestination("https://backup.sloths.com/old.txt")
+	req.SetOverwrite(true)
+	depth, _ = req.Depth()
+	require.Equal(t, Depth0, depth)
+	dest, _ = req.Destination()
+	require.Equal(t, "https://backup.sloths.com/old.txt", dest)
+	require.True(t, req.Overwrite())
+}
+
+func TestWebDAVDefaults(t *testing.T) {
+	req, err := Parse(`curl -H 'Depth: 2' https://dav.sloths.com/files/`)
+	require.NoError(t, err)
+	require.True(t, req.IsWebDAV())
+	_, ok := req.Depth()
+	require.False(t, ok)
+	_, ok = req.Destination()
+	require.False(t, ok)
+	require.True(t, req.Overwrite())
+
+	req, err = Parse(`curl https://dav.sloths.com/files/`)
+	require.NoError(t, err)
+	require.False(t, req.IsWebDAV())
+
+	req, err = Parse(`curl -X mkcol https://dav.sloths.com/files/new/`)
+	require.NoError(t, err)
+	require.Equal(t, MethodMkcol, req.Method)
+	require.True(t, req.IsWebDAV())
+}
@@ -0,0 +1,120 @@
+package gcurl
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DumpWire renders the raw HTTP/1.1 request: request line, headers and
+// body, as net/http writes them. Headers added later by the transport
+// (Accept-Encoding, proxy and connection headers) are not included.
+func (r *Request) DumpWire() ([]byte, error) {
+	hreq, err := r.ToHTTPRequest(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := hreq.Write(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WireSize estimates the bytes DumpWire would produce, split between the
+// request head and the body, without building the body. BodyFile,
+// UploadFile and form files are only stat'ed; missing ones and standard
+// input count as empty.
+func (r *Request) WireSize() (headersBytes, bodyBytes int64) {
+	var contentType string
+	switch {
+	case r.Body != "":
+		bodyBytes = int64(len(r.Body))
+	case r.BodyFile != "":
+		if fi, err := os.Stat(r.BodyFile); err == nil {
+			bodyBytes = fi.Size()
+		}
+	case r.UploadFile != "":
+		if fi, err := os.Stat(r.UploadFile); err == nil && r.UploadFile != "-" {
+			bodyBytes = fi.Size()
+		}
+	case len(r.Form) > 0:
+		bodyBytes, contentType = r.formSize()
+	}
+
+	target, host := r.URL, ""
+	if u, err := r.httpURL(); err == nil {
+		target, host = u.RequestURI(), u.Host
+	}
+	line := func(k, v string) {
+		headersBytes += int64(len(k) + len(": ") + len(v) + len("\r\n"))
+	}
+	headersBytes = int64(len(r.Method) + len(" ") + len(target) + len(" HTTP/1.1\r\n"))
+
+	hasUA := false
+	for k, v := range r.Header {
+		switch strings.ToLower(k) {
+		case "host":
+			host = v
+		case "content-length":
+		case KeyContentType:
+			if contentType == "" {
+				line(k, v)
+			}
+		case KeyUserAgent:
+			hasUA = true
+			line(k, v)
+		default:
+			for _, v := range r.HeaderValues(k) {
+				line(k, v)
+			}
+		}
+	}
+	line("Host", host)
+	if !hasUA {
+		line("User-Agent", "Go-http-client/1.1")
+	}
+	if contentType != "" {
+		line("Content-Type", contentType)
+	}
+	if bodyBytes > 0 {
+		line("Content-Length", strconv.FormatInt(bodyBytes, 10))
+	}
+	headersBytes += int64(len("\r\n"))
+	return headersBytes, bodyBytes
+}
+
+// formSize returns the multipart body size of the form and its
+// Content-Type.
+func (r *Request) formSize() (int64, string) {
+	cw := &countingWriter{}
+	w, err := r.newFormWriter(cw)
+	if err != nil {
+		// Sending fails as well; estimate with a random boundary.
+		w = multipart.NewWriter(cw)
+	}
+	for _, f := range r.Form {
+		// Errors come from the writer, which never fails.
+		w.CreatePart(f.partHeader())
+		if f.File == "" {
+			cw.n += int64(len(f.Value))
+		} else if fi, err := os.Stat(f.File); err == nil {
+			cw.n += fi.Size()
+		}
+	}
+	w.Close()
+	return cw.n, w.FormDataContentType()
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
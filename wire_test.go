@@ -0,0 +1,57 @@
+package gcurl
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpWire(t *testing.T) {
+	req, err := Parse(`curl -H 'X-Sloth: sid' -d 'name=sid' 'https://api.site.com/sloths?kind=two'`)
+	require.NoError(t, err)
+
+	wire, err := req.DumpWire()
+	require.NoError(t, err)
+	require.Equal(t, "POST /sloths?kind=two HTTP/1.1\r\n"+
+		"Host: api.site.com\r\n"+
+		"User-Agent: Go-http-client/1.1\r\n"+
+		"Content-Length: 8\r\n"+
+		"Content-Type: application/x-www-form-urlencoded\r\n"+
+		"X-Sloth: sid\r\n"+
+		"\r\n"+
+		"name=sid", string(wire))
+}
+
+func TestDumpWireInvalidURL(t *testing.T) {
+	_, err := (&Request{Method: "GET", URL: "http://[::1"}).DumpWire()
+	require.Error(t, err)
+}
+
+func TestWireSize(t *testing.T) {
+	dir := t.TempDir()
+	avatar := filepath.Join(dir, "sid.png")
+	require.NoError(t, os.WriteFile(avatar, bytes.Repeat([]byte{0x89}, 1000), 0o644))
+
+	for _, cmd := range []string{
+		`curl https://api.site.com`,
+		`curl -H 'X-Sloth: sid' -A 'sloth/1.0' -d 'name=sid' 'https://api.site.com/sloths?kind=two'`,
+		`curl -X PUT -H 'Host: internal.site.com' -H 'Content-Length: 7' -d '{"a":1}' -H 'Content-Type: application/json' https://10.0.0.1/a`,
+		`curl -F name=sid -F 'avatar=@` + avatar + `;type=image/png' https://api.site.com/upload`,
+		`curl -H 'Content-Type: multipart/form-data; boundary=sloth' -F name=sid https://api.site.com/upload`,
+	} {
+		req, err := Parse(cmd)
+		require.NoError(t, err)
+		hreq, err := req.ToHTTPRequest(context.Background())
+		require.NoError(t, err)
+
+		wire, err := req.DumpWire()
+		require.NoError(t, err)
+		headers, body := req.WireSize()
+		require.Equal(t, int64(len(wire)), headers+body, cmd)
+		require.Equal(t, hreq.ContentLength, body, cmd)
+	}
+}